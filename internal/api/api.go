@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"runtime"
 
+	"miniflux.app/v2/internal/embedding"
 	"miniflux.app/v2/internal/http/response/json"
 	"miniflux.app/v2/internal/storage"
 	"miniflux.app/v2/internal/version"
@@ -16,14 +17,16 @@ import (
 )
 
 type handler struct {
-	store  *storage.Storage
-	pool   *worker.Pool
-	router *mux.Router
+	store    *storage.Storage
+	pool     *worker.Pool
+	router   *mux.Router
+	embedder embedding.Embedder
 }
 
 // Serve declares API routes for the application.
-func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
-	handler := &handler{store, pool, router}
+// The semantic search endpoint is only registered when an embedder is given.
+func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool, embedder embedding.Embedder) {
+	handler := &handler{store, pool, router, embedder}
 
 	sr := router.PathPrefix("/v1").Subrouter()
 	middleware := newMiddleware(store)
@@ -65,12 +68,66 @@ func Serve(router *mux.Router, store *storage.Storage, pool *worker.Pool) {
 	sr.HandleFunc("/feeds/{feedID}/entries/{entryID}", handler.getFeedEntry).Methods(http.MethodGet)
 	sr.HandleFunc("/entries", handler.getEntries).Methods(http.MethodGet)
 	sr.HandleFunc("/entries", handler.setEntryStatus).Methods(http.MethodPut)
+	if embedder != nil {
+		sr.HandleFunc("/entries/search/semantic", handler.semanticSearchEntries).Methods(http.MethodGet)
+	}
 	sr.HandleFunc("/entries/{entryID}", handler.getEntry).Methods(http.MethodGet)
 	sr.HandleFunc("/entries/{entryID}", handler.updateEntry).Methods(http.MethodPut)
 	sr.HandleFunc("/entries/{entryID}/bookmark", handler.toggleStarred).Methods(http.MethodPut)
 	sr.HandleFunc("/entries/{entryID}/star", handler.toggleStarred).Methods(http.MethodPut)
 	sr.HandleFunc("/entries/{entryID}/save", handler.saveEntry).Methods(http.MethodPost)
 	sr.HandleFunc("/entries/{entryID}/fetch-content", handler.fetchContent).Methods(http.MethodGet)
+	sr.HandleFunc("/entries/{entryID}/embedding", handler.getEntryEmbedding).Methods(http.MethodGet)
+	sr.HandleFunc("/entries/{entryID}/embedding", handler.importEntryEmbedding).Methods(http.MethodPost)
+	sr.HandleFunc("/entries/{entryID}/similar", handler.getSimilarEntries).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters", handler.getClusters).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters", handler.createCluster).Methods(http.MethodPost)
+	sr.HandleFunc("/clusters/batch", handler.createClustersBatch).Methods(http.MethodPost)
+	sr.HandleFunc("/clusters/run", handler.runClustering).Methods(http.MethodPost)
+	sr.HandleFunc("/clusters/count", handler.countClusters).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters/unviewed", handler.getUnviewedClusters).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters/expiring", handler.getClustersExpiringSoon).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters/mark-viewed", handler.markClustersViewed).Methods(http.MethodPost)
+	sr.HandleFunc("/clusters/{clusterID}", handler.removeCluster).Methods(http.MethodDelete)
+	sr.HandleFunc("/clusters/{clusterID}/entries", handler.getClusterEntries).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters/{clusterID}/entries", handler.addEntriesToCluster).Methods(http.MethodPost)
+	sr.HandleFunc("/clusters/{clusterID}/entries/{entryID}", handler.removeEntryFromCluster).Methods(http.MethodDelete)
+	sr.HandleFunc("/clusters/{clusterID}/mark-read", handler.markClusterAsRead).Methods(http.MethodPost)
+	sr.HandleFunc("/clusters/{clusterID}/tags", handler.addTagToClusterEntries).Methods(http.MethodPost)
+	sr.HandleFunc("/clusters/{clusterID}/convert-to-tag", handler.convertClusterToTag).Methods(http.MethodPost)
+	sr.HandleFunc("/clusters/{clusterID}/tags/{tagID}", handler.removeTagFromClusterEntries).Methods(http.MethodDelete)
+	sr.HandleFunc("/clusters/{clusterID}/related", handler.getRelatedClusters).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters/{clusterID}/timeline", handler.getClusterTimeline).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters/{clusterID}/date-range", handler.getClusterDateRange).Methods(http.MethodGet)
+	sr.HandleFunc("/clusters/{clusterID}/expiry", handler.updateClusterExpiry).Methods(http.MethodPut)
+	sr.HandleFunc("/entries/{entryID}/tags", handler.getEntryTags).Methods(http.MethodGet)
+	sr.HandleFunc("/entries/{entryID}/available-tags", handler.getAvailableEntryTags).Methods(http.MethodGet)
+	sr.HandleFunc("/entries/{entryID}/suggested-tags", handler.getSuggestedEntryTags).Methods(http.MethodGet)
+	sr.HandleFunc("/entries/{entryID}/tags", handler.addTagsToEntry).Methods(http.MethodPost)
+	sr.HandleFunc("/entries/{entryID}/tags/copy", handler.copyEntryTags).Methods(http.MethodPost)
+	sr.HandleFunc("/entries/{entryID}/tags/reviewed", handler.markEntryTagsReviewed).Methods(http.MethodPut)
+	sr.HandleFunc("/entries/{entryID}/tags/{tagID}", handler.removeTagFromEntry).Methods(http.MethodDelete)
+	sr.HandleFunc("/entries/{entryID}/tags/{tagID}/confirm", handler.confirmAutoTag).Methods(http.MethodPut)
+	sr.HandleFunc("/entries/{entryID}/tags/{tagID}/dismiss", handler.dismissAutoTag).Methods(http.MethodPut)
+	sr.HandleFunc("/entries/{entryID}/tags/{tagID}/revert", handler.revertTagToAuto).Methods(http.MethodPut)
+	sr.HandleFunc("/tags", handler.getTags).Methods(http.MethodGet)
+	sr.HandleFunc("/tags", handler.createTag).Methods(http.MethodPost)
+	sr.HandleFunc("/tags/by-name", handler.getTagByName).Methods(http.MethodGet)
+	sr.HandleFunc("/tags/unused", handler.getUnusedTags).Methods(http.MethodGet)
+	sr.HandleFunc("/tags/unused", handler.removeUnusedTags).Methods(http.MethodDelete)
+	sr.HandleFunc("/tags/bulk-rename", handler.bulkRenameTags).Methods(http.MethodPost)
+	sr.HandleFunc("/tags/review/count", handler.countTagReviewEntries).Methods(http.MethodGet)
+	sr.HandleFunc("/tags/histogram", handler.getTagCreationHistogram).Methods(http.MethodGet)
+	sr.HandleFunc("/tags/auto/confirm", handler.confirmAllAutoTags).Methods(http.MethodPut)
+	sr.HandleFunc("/tags/{tagID}", handler.updateTag).Methods(http.MethodPut)
+	sr.HandleFunc("/tags/{tagID}", handler.removeTag).Methods(http.MethodDelete)
+	sr.HandleFunc("/tags/{tagID}/entries", handler.getEntriesByTag).Methods(http.MethodGet)
+	sr.HandleFunc("/tags/{tagID}/merge", handler.mergeTags).Methods(http.MethodPost)
+	sr.HandleFunc("/tags/{tagID}/convert-to-cluster", handler.convertTagToCluster).Methods(http.MethodPost)
+	sr.HandleFunc("/tags/{tagID}/merge/preview", handler.previewTagMerge).Methods(http.MethodPost)
+	sr.HandleFunc("/tags/{tagID}/related", handler.getRelatedTags).Methods(http.MethodGet)
+	sr.HandleFunc("/tags/{tagID}/feeds", handler.getTagFeedBreakdown).Methods(http.MethodGet)
+	sr.HandleFunc("/tags/{tagID}/export", handler.exportTagEntries).Methods(http.MethodGet)
 	sr.HandleFunc("/flush-history", handler.flushHistory).Methods(http.MethodPut, http.MethodDelete)
 	sr.HandleFunc("/icons/{iconID}", handler.getIconByIconID).Methods(http.MethodGet)
 	sr.HandleFunc("/enclosures/{enclosureID}", handler.getEnclosureByID).Methods(http.MethodGet)
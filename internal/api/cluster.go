@@ -0,0 +1,492 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package api // import "miniflux.app/v2/internal/api"
+
+import (
+	json_parser "encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"miniflux.app/v2/internal/clustering"
+	"miniflux.app/v2/internal/http/request"
+	"miniflux.app/v2/internal/http/response/json"
+	"miniflux.app/v2/internal/model"
+	"miniflux.app/v2/internal/storage"
+	"miniflux.app/v2/internal/validator"
+)
+
+func (h *handler) getClusters(w http.ResponseWriter, r *http.Request) {
+	sortOrder := request.QueryStringParam(r, "order", model.ClusterSortCreatedAt)
+	if sortOrder != model.ClusterSortCreatedAt && sortOrder != model.ClusterSortFreshness && sortOrder != model.ClusterSortUnread {
+		json.BadRequest(w, r, errors.New("invalid order, must be created_at, freshness or unread"))
+		return
+	}
+
+	source := request.QueryStringParam(r, "source", "")
+	if source != "" && !validator.IsValidClusterSource(source) {
+		json.BadRequest(w, r, errors.New("invalid source, must be manual or auto"))
+		return
+	}
+
+	multiFeedOnly := request.QueryBoolParam(r, "multi_feed_only", false)
+	clusters, err := h.store.Clusters(request.UserID(r), sortOrder, multiFeedOnly, source)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, clusters)
+}
+
+func (h *handler) countClusters(w http.ResponseWriter, r *http.Request) {
+	includeExpired := request.QueryBoolParam(r, "include_expired", false)
+	count, err := h.store.CountClusters(request.UserID(r), includeExpired)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, &clusterCountResponse{Count: count})
+}
+
+func (h *handler) getUnviewedClusters(w http.ResponseWriter, r *http.Request) {
+	clusters, err := h.store.UnviewedClusters(request.UserID(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, clusters)
+}
+
+func (h *handler) getClustersExpiringSoon(w http.ResponseWriter, r *http.Request) {
+	withinHours := request.QueryIntParam(r, "within_hours", 24)
+	if withinHours <= 0 {
+		json.BadRequest(w, r, errors.New("within_hours must be a positive number"))
+		return
+	}
+
+	clusters, err := h.store.ClustersExpiringSoon(request.UserID(r), time.Duration(withinHours)*time.Hour)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, clusters)
+}
+
+func (h *handler) markClustersViewed(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.MarkClustersViewed(request.UserID(r)); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) createCluster(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	var clusterRequest model.ClusterSpec
+	if err := json_parser.NewDecoder(r.Body).Decode(&clusterRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateClusterSpec(&clusterRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	if !h.store.EntryIDsExist(userID, clusterRequest.EntryIDs) {
+		json.BadRequest(w, r, errors.New("invalid entry IDs"))
+		return
+	}
+
+	if clusterRequest.Source == "" {
+		clusterRequest.Source = model.ClusterSourceManual
+	}
+
+	cluster, err := h.store.CreateCluster(userID, clusterRequest.Name, clusterRequest.Source, clusterRequest.ExpiresAt, clusterRequest.EntryIDs[0])
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if err := h.store.AddEntriesToCluster(cluster.ID, clusterRequest.EntryIDs, clusterRequest.CollapseDuplicates); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	cluster, err = h.store.GetClusterWithEntries(userID, cluster.ID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, cluster)
+}
+
+func (h *handler) createClustersBatch(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	var batchRequest model.ClusterBatchCreationRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&batchRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateClusterBatchCreation(&batchRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	clusters, err := h.store.CreateClustersBatch(userID, batchRequest.Clusters)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, clusters)
+}
+
+func (h *handler) markClusterAsRead(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	if !h.store.ClusterIDExists(userID, clusterID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	updated, err := h.store.MarkClusterEntriesAsRead(userID, clusterID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, &clusterMarkAsReadResponse{Updated: updated})
+}
+
+func (h *handler) removeCluster(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+	markRead := request.QueryBoolParam(r, "mark_read", false)
+
+	updated, err := h.store.RemoveCluster(userID, clusterID, markRead)
+	if err != nil {
+		if errors.Is(err, storage.ErrClusterNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if markRead {
+		json.OK(w, r, &clusterMarkAsReadResponse{Updated: updated})
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) getClusterEntries(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	if !h.store.ClusterIDExists(userID, clusterID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	limit := request.QueryIntParam(r, "limit", 100)
+	offset := request.QueryIntParam(r, "offset", 0)
+	if err := validator.ValidateRange(offset, limit); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	builder := h.store.NewEntryQueryBuilder(userID)
+	builder.WithClusterID(clusterID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithSorting("published_at", "DESC")
+	builder.WithOffset(offset)
+	builder.WithLimit(limit)
+	builder.WithEnclosures()
+
+	configureFilters(builder, r)
+
+	entries, err := builder.GetEntries()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	count, err := builder.CountEntries()
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, &entriesResponse{Total: count, Entries: entries})
+}
+
+func (h *handler) addEntriesToCluster(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	if !h.store.ClusterIDExists(userID, clusterID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	var entriesRequest model.ClusterEntriesRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&entriesRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateClusterEntriesRequest(&entriesRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	if !h.store.EntryIDsExist(userID, entriesRequest.EntryIDs) {
+		json.BadRequest(w, r, errors.New("invalid entry IDs"))
+		return
+	}
+
+	if err := h.store.AddEntriesToCluster(clusterID, entriesRequest.EntryIDs, entriesRequest.CollapseDuplicates); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	cluster, err := h.store.GetClusterWithEntries(userID, clusterID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, cluster)
+}
+
+func (h *handler) removeEntryFromCluster(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	if !h.store.ClusterIDExists(userID, clusterID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveEntryFromCluster(clusterID, entryID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) updateClusterExpiry(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	var expiryRequest model.ClusterExpiryRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&expiryRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateClusterExpiryRequest(&expiryRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	if err := h.store.ExtendClusterExpiry(userID, clusterID, expiryRequest.ExpiresAt); err != nil {
+		if errors.Is(err, storage.ErrClusterNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	cluster, err := h.store.ClusterByID(userID, clusterID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, cluster)
+}
+
+func (h *handler) addTagToClusterEntries(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	var tagRequest model.ClusterTagRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&tagRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateClusterTagRequest(&tagRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	if err := h.store.AddTagToClusterEntries(userID, clusterID, tagRequest.TagName, tagRequest.Source); err != nil {
+		if errors.Is(err, storage.ErrClusterNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) convertClusterToTag(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	var conversionRequest model.ClusterConversionRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&conversionRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateClusterConversionRequest(&conversionRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	tag, err := h.store.ConvertClusterToTag(userID, clusterID, conversionRequest.TagName, conversionRequest.DeleteCluster)
+	if err != nil {
+		if errors.Is(err, storage.ErrClusterNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, tag)
+}
+
+func (h *handler) removeTagFromClusterEntries(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+	tagID := request.RouteInt64Param(r, "tagID")
+
+	if !h.store.TagIDExists(userID, tagID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RemoveTagFromClusterEntries(userID, clusterID, tagID); err != nil {
+		if errors.Is(err, storage.ErrClusterNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) getRelatedClusters(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	limit := request.QueryIntParam(r, "limit", 10)
+	if err := validator.ValidateRange(0, limit); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	clusters, err := h.store.RelatedClusters(userID, clusterID, limit)
+	if err != nil {
+		if errors.Is(err, storage.ErrClusterNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, clusters)
+}
+
+func (h *handler) getClusterTimeline(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	timeline, err := h.store.ClusterEntryTimeline(userID, clusterID)
+	if err != nil {
+		if errors.Is(err, storage.ErrClusterNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, timeline)
+}
+
+func (h *handler) getClusterDateRange(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	clusterID := request.RouteInt64Param(r, "clusterID")
+
+	earliest, latest, found, err := h.store.ClusterDateRange(userID, clusterID)
+	if err != nil {
+		if errors.Is(err, storage.ErrClusterNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	response := &clusterDateRangeResponse{}
+	if found {
+		response.Earliest = &earliest
+		response.Latest = &latest
+	}
+
+	json.OK(w, r, response)
+}
+
+func (h *handler) runClustering(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	opts := clustering.DefaultOptions()
+	opts.UnclusteredOnly = request.QueryBoolParam(r, "unclustered_only", false)
+	opts.MultiFeedOnly = request.QueryBoolParam(r, "multi_feed_only", false)
+
+	if request.QueryBoolParam(r, "dry_run", false) {
+		specs, err := clustering.PreviewClustering(h.store, userID, opts)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		json.OK(w, r, &clusteringPreviewResponse{Total: len(specs), Clusters: specs})
+		return
+	}
+
+	clusters, err := clustering.RunClustering(h.store, userID, opts)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, clusters)
+}
@@ -396,6 +396,26 @@ func configureFilters(builder *storage.EntryQueryBuilder, r *http.Request) {
 		}
 	}
 
+	if request.HasQueryParam(r, "summarized") {
+		if request.QueryBoolParam(r, "summarized", false) {
+			builder.WithSummary()
+		} else {
+			builder.WithoutSummary()
+		}
+	}
+
+	if request.HasQueryParam(r, "embedded") {
+		if request.QueryBoolParam(r, "embedded", false) {
+			builder.WithEmbedding()
+		} else {
+			builder.WithoutEmbedding()
+		}
+	}
+
+	if request.QueryBoolParam(r, "untagged", false) {
+		builder.WithoutAnyTag()
+	}
+
 	if searchQuery := request.QueryStringParam(r, "search", ""); searchQuery != "" {
 		builder.WithSearchQuery(searchQuery)
 	}
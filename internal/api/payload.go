@@ -4,6 +4,8 @@
 package api // import "miniflux.app/v2/internal/api"
 
 import (
+	"time"
+
 	"miniflux.app/v2/internal/model"
 )
 
@@ -22,6 +24,68 @@ type feedCreationResponse struct {
 	FeedID int64 `json:"feed_id"`
 }
 
+type tagCleanupResponse struct {
+	Removed int64 `json:"removed"`
+}
+
+type tagBulkRenameResponse struct {
+	Renamed int `json:"renamed"`
+}
+
+type tagMergePreviewResponse struct {
+	Moved     int `json:"moved"`
+	Conflicts int `json:"conflicts"`
+}
+
+type tagReviewCountResponse struct {
+	Count int `json:"count"`
+}
+
+type clusterCountResponse struct {
+	Count int `json:"count"`
+}
+
+type clusterDateRangeResponse struct {
+	Earliest *time.Time `json:"earliest,omitempty"`
+	Latest   *time.Time `json:"latest,omitempty"`
+}
+
+type autoTagConfirmationResponse struct {
+	Confirmed int64 `json:"confirmed"`
+}
+
+type scoredEntriesResponse struct {
+	Total   int                 `json:"total"`
+	Entries []model.ScoredEntry `json:"entries"`
+}
+
+type entryEmbeddingResponse struct {
+	EntryID    int64     `json:"entry_id"`
+	Dimension  int       `json:"dimension"`
+	Normalized bool      `json:"normalized"`
+	Model      string    `json:"model,omitempty"`
+	Embedding  string    `json:"embedding,omitempty"`
+	Vector     []float32 `json:"vector,omitempty"`
+}
+
+type tagExportEntry struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	FeedTitle   string    `json:"feed"`
+	PublishedAt time.Time `json:"published_at"`
+	Tags        []string  `json:"tags"`
+}
+
+type clusteringPreviewResponse struct {
+	Total    int                 `json:"total"`
+	Clusters []model.ClusterSpec `json:"clusters"`
+}
+
+type clusterMarkAsReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
 type versionResponse struct {
 	Version   string `json:"version"`
 	Commit    string `json:"commit"`
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package api // import "miniflux.app/v2/internal/api"
+
+import (
+	"encoding/base64"
+	json_parser "encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"miniflux.app/v2/internal/embedding"
+	"miniflux.app/v2/internal/http/request"
+	"miniflux.app/v2/internal/http/response/json"
+	"miniflux.app/v2/internal/model"
+	"miniflux.app/v2/internal/validator"
+)
+
+func (h *handler) semanticSearchEntries(w http.ResponseWriter, r *http.Request) {
+	searchQuery := strings.TrimSpace(request.QueryStringParam(r, "q", ""))
+	if searchQuery == "" {
+		json.BadRequest(w, r, errors.New("the q parameter is required"))
+		return
+	}
+
+	limit := request.QueryIntParam(r, "limit", 20)
+	if err := validator.ValidateRange(0, limit); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	queryVector, err := h.embedder.Embed(r.Context(), searchQuery)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	scoredEntries, err := h.store.FindScoredEntriesBySimilarityToVector(request.UserID(r), queryVector, limit)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, &scoredEntriesResponse{Total: len(scoredEntries), Entries: scoredEntries})
+}
+
+func (h *handler) getEntryEmbedding(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	format := request.QueryStringParam(r, "format", "base64")
+	if format != "base64" && format != "float" {
+		json.BadRequest(w, r, errors.New("invalid format, must be base64 or float"))
+		return
+	}
+
+	data, normalized, modelName, err := h.store.EntryEmbedding(userID, entryID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if data == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	vector, err := embedding.Decode(data)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	response := &entryEmbeddingResponse{EntryID: entryID, Dimension: len(vector), Normalized: normalized, Model: modelName}
+	if format == "float" {
+		response.Vector = vector
+	} else {
+		response.Embedding = base64.StdEncoding.EncodeToString(data)
+	}
+
+	json.OK(w, r, response)
+}
+
+func (h *handler) importEntryEmbedding(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	if !h.store.EntryIDsExist(userID, []int64{entryID}) {
+		json.NotFound(w, r)
+		return
+	}
+
+	var embeddingRequest model.EntryEmbeddingRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&embeddingRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	// Only the embeddings of the same model constrain the dimension, so vectors from another model can be imported.
+	dimension, err := h.store.EmbeddingDimension(userID, embeddingRequest.Model)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if err := validator.ValidateEntryEmbeddingRequest(&embeddingRequest, dimension); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if err := h.store.UpdateEntryEmbedding(entryID, embedding.Encode(embeddingRequest.Vector), embeddingRequest.Model); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) getSimilarEntries(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	limit := request.QueryIntParam(r, "limit", 10)
+	if err := validator.ValidateRange(0, limit); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if !h.store.EntryIDsExist(userID, []int64{entryID}) {
+		json.NotFound(w, r)
+		return
+	}
+
+	scoredEntries, err := h.store.FindSimilarEntries(userID, entryID, limit)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, &scoredEntriesResponse{Total: len(scoredEntries), Entries: scoredEntries})
+}
@@ -5,35 +5,96 @@ package api // import "miniflux.app/v2/internal/api"
 
 import (
 	json_parser "encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"miniflux.app/v2/internal/http/request"
 	"miniflux.app/v2/internal/http/response/json"
+	"miniflux.app/v2/internal/locale"
 	"miniflux.app/v2/internal/model"
+	"miniflux.app/v2/internal/storage"
 	"miniflux.app/v2/internal/validator"
 )
 
+// defaultTagSuggestionPopularityWeight is the share of the overall tag usage in the ranking of suggested tags.
+const defaultTagSuggestionPopularityWeight = 0.3
+
 func (h *handler) getTags(w http.ResponseWriter, r *http.Request) {
 	userID := request.UserID(r)
 	includeCounts := request.QueryStringParam(r, "counts", "false")
+	categoryID := request.QueryInt64Param(r, "category_id", 0)
+
+	createdSince, err := parseTimeQueryParam(r, "created_since")
+	if err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	createdBefore, err := parseTimeQueryParam(r, "created_before")
+	if err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	tagIDs, err := parseInt64ListQueryParam(r, "ids")
+	if err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
 
 	var tags model.Tags
-	var err error
 
-	if includeCounts == "true" {
-		tags, err = h.store.TagsWithCount(userID)
+	if len(tagIDs) > 0 {
+		tags, err = h.store.TagsByIDs(userID, tagIDs)
+	} else if includeCounts == "true" {
+		tags, err = h.store.TagsWithCount(userID, categoryID, createdSince, createdBefore)
 	} else {
-		tags, err = h.store.Tags(userID)
+		tags, err = h.store.Tags(userID, categoryID, createdSince, createdBefore)
+	}
+
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
 	}
 
+	tagVersion, err := h.store.TagVersion(userID)
 	if err != nil {
 		json.ServerError(w, r, err)
 		return
 	}
 
+	w.Header().Set("X-Tag-Version", strconv.FormatInt(tagVersion, 10))
 	json.OK(w, r, tags)
 }
 
+func (h *handler) getTagByName(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	name := request.QueryStringParam(r, "name", "")
+	if name == "" {
+		json.BadRequest(w, r, errors.New("the name parameter is required"))
+		return
+	}
+
+	categoryID := request.QueryInt64Param(r, "category_id", 0)
+	tag, err := h.store.TagByNameWithCount(userID, categoryID, name)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if tag == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	json.OK(w, r, tag)
+}
+
 func (h *handler) createTag(w http.ResponseWriter, r *http.Request) {
 	userID := request.UserID(r)
 
@@ -90,9 +151,127 @@ func (h *handler) updateTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if tagModificationRequest.Pinned != nil {
+		if err := h.store.PinTag(userID, tag.ID, tag.Pinned); err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+	}
+
 	json.OK(w, r, tag)
 }
 
+func (h *handler) bulkRenameTags(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	var renameRequest model.TagBulkRenameRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&renameRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateTagBulkRename(&renameRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	renamed, err := h.store.BulkRenameTags(userID, renameRequest.Pattern, renameRequest.Replacement)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, &tagBulkRenameResponse{Renamed: renamed})
+}
+
+func (h *handler) mergeTags(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	tagID := request.RouteInt64Param(r, "tagID")
+
+	var mergeRequest model.TagMergeRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&mergeRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateTagMergeRequest(tagID, &mergeRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	if err := h.store.MergeTags(userID, tagID, mergeRequest.SourceTagIDs); err != nil {
+		if errors.Is(err, storage.ErrTagNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		if errors.Is(err, storage.ErrNoTagsToMerge) {
+			json.BadRequest(w, r, err)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) convertTagToCluster(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	tagID := request.RouteInt64Param(r, "tagID")
+
+	var conversionRequest model.TagConversionRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&conversionRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateTagConversionRequest(&conversionRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	cluster, err := h.store.ConvertTagToCluster(userID, tagID, conversionRequest.ClusterName, conversionRequest.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, storage.ErrTagNotFound) {
+			json.NotFound(w, r)
+			return
+		}
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.Created(w, r, cluster)
+}
+
+func (h *handler) previewTagMerge(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	tagID := request.RouteInt64Param(r, "tagID")
+
+	if !h.store.TagIDExists(userID, tagID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	var mergeRequest model.TagMergeRequest
+	if err := json_parser.NewDecoder(r.Body).Decode(&mergeRequest); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	if validationErr := validator.ValidateTagMergeRequest(tagID, &mergeRequest); validationErr != nil {
+		json.BadRequest(w, r, validationErr.Error())
+		return
+	}
+
+	moved, conflicts, err := h.store.PreviewTagMerge(userID, tagID, mergeRequest.SourceTagIDs)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, &tagMergePreviewResponse{Moved: moved, Conflicts: conflicts})
+}
+
 func (h *handler) removeTag(w http.ResponseWriter, r *http.Request) {
 	userID := request.UserID(r)
 	tagID := request.RouteInt64Param(r, "tagID")
@@ -110,6 +289,134 @@ func (h *handler) removeTag(w http.ResponseWriter, r *http.Request) {
 	json.NoContent(w, r)
 }
 
+func (h *handler) getUnusedTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.store.UnusedTags(request.UserID(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, tags)
+}
+
+// removeUnusedTags is a maintenance endpoint for administrators.
+// It removes the unused tags of the user given by user_id, or of every user when it's absent.
+func (h *handler) removeUnusedTags(w http.ResponseWriter, r *http.Request) {
+	if !request.IsAdminUser(r) {
+		json.Forbidden(w, r)
+		return
+	}
+
+	olderThanDays := request.QueryIntParam(r, "older_than_days", 0)
+	if olderThanDays < 0 {
+		json.BadRequest(w, r, errors.New("older_than_days must be a positive number"))
+		return
+	}
+
+	var userIDs []int64
+	if userID := request.QueryInt64Param(r, "user_id", 0); userID > 0 {
+		user, err := h.store.UserByID(userID)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		if user == nil {
+			json.NotFound(w, r)
+			return
+		}
+
+		userIDs = append(userIDs, user.ID)
+	} else {
+		users, err := h.store.Users()
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		for _, user := range users {
+			userIDs = append(userIDs, user.ID)
+		}
+	}
+
+	olderThan := time.Now().AddDate(0, 0, -olderThanDays)
+	var removed int64
+	for _, userID := range userIDs {
+		count, err := h.store.RemoveUnusedTags(userID, olderThan)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+		removed += count
+	}
+
+	slog.Info("Unused tags cleanup completed",
+		slog.Int("nb_users", len(userIDs)),
+		slog.Int("older_than_days", olderThanDays),
+		slog.Int64("unused_tags_removed", removed),
+	)
+
+	json.OK(w, r, &tagCleanupResponse{Removed: removed})
+}
+
+func (h *handler) getRelatedTags(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	tagID := request.RouteInt64Param(r, "tagID")
+
+	if !h.store.TagIDExists(userID, tagID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	limit := request.QueryIntParam(r, "limit", 10)
+	if err := validator.ValidateRange(0, limit); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	relatedTags, err := h.store.RelatedTags(userID, tagID, limit)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, relatedTags)
+}
+
+func (h *handler) getTagCreationHistogram(w http.ResponseWriter, r *http.Request) {
+	bucket := request.QueryStringParam(r, "bucket", model.TimelineBucketDay)
+	if err := validator.ValidateTimelineBucket(bucket); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	histogram, err := h.store.TagCreationHistogram(request.UserID(r), bucket)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, histogram)
+}
+
+func (h *handler) getTagFeedBreakdown(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	tagID := request.RouteInt64Param(r, "tagID")
+
+	if !h.store.TagIDExists(userID, tagID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	feedCounts, err := h.store.TagFeedBreakdown(userID, tagID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, feedCounts)
+}
+
 func (h *handler) getEntryTags(w http.ResponseWriter, r *http.Request) {
 	userID := request.UserID(r)
 	entryID := request.RouteInt64Param(r, "entryID")
@@ -123,6 +430,88 @@ func (h *handler) getEntryTags(w http.ResponseWriter, r *http.Request) {
 	json.OK(w, r, entryTags)
 }
 
+func (h *handler) getAvailableEntryTags(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	if !h.store.EntryIDsExist(userID, []int64{entryID}) {
+		json.NotFound(w, r)
+		return
+	}
+
+	tags, err := h.store.TagsNotOnEntry(userID, entryID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, tags)
+}
+
+func (h *handler) getSuggestedEntryTags(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	limit := request.QueryIntParam(r, "limit", 10)
+	if err := validator.ValidateRange(0, limit); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	popularityWeight := defaultTagSuggestionPopularityWeight
+	if value := request.QueryStringParam(r, "popularity_weight", ""); value != "" {
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 || weight > 1 {
+			json.BadRequest(w, r, errors.New("popularity_weight must be a number between 0 and 1"))
+			return
+		}
+		popularityWeight = weight
+	}
+
+	if !h.store.EntryIDsExist(userID, []int64{entryID}) {
+		json.NotFound(w, r)
+		return
+	}
+
+	tags, err := h.store.SuggestTagsForEntry(userID, entryID, limit, popularityWeight)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, tags)
+}
+
+func (h *handler) copyEntryTags(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+	fromEntryID := request.QueryInt64Param(r, "from_entry_id", 0)
+
+	if fromEntryID == 0 || fromEntryID == entryID {
+		json.BadRequest(w, r, errors.New("from_entry_id must be the ID of another entry"))
+		return
+	}
+
+	if !h.store.EntryIDsExist(userID, []int64{entryID, fromEntryID}) {
+		json.NotFound(w, r)
+		return
+	}
+
+	includeAuto := request.QueryBoolParam(r, "include_auto", true)
+	if err := h.store.CopyEntryTags(userID, fromEntryID, entryID, includeAuto); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	entryTags, err := h.store.GetEntryTags(userID, entryID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, entryTags)
+}
+
 func (h *handler) addTagsToEntry(w http.ResponseWriter, r *http.Request) {
 	userID := request.UserID(r)
 	entryID := request.RouteInt64Param(r, "entryID")
@@ -144,6 +533,10 @@ func (h *handler) addTagsToEntry(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.store.AddTagsToEntryByName(userID, entryID, tagRequest.TagNames, source); err != nil {
+		if errors.Is(err, storage.ErrEntryTagLimitReached) {
+			json.BadRequest(w, r, locale.NewLocalizedError("error.entry_tag_limit_reached").Error())
+			return
+		}
 		json.ServerError(w, r, err)
 		return
 	}
@@ -179,13 +572,39 @@ func (h *handler) getEntriesByTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limit := request.QueryIntParam(r, "limit", 100)
+	offset := request.QueryIntParam(r, "offset", 0)
+	if err := validator.ValidateRange(offset, limit); err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
+	source := request.QueryStringParam(r, "source", "")
+	if source != "" {
+		if err := validator.ValidateTagSource(source); err != nil {
+			json.BadRequest(w, r, err)
+			return
+		}
+	}
+
+	excludedTagIDs, err := parseInt64ListQueryParam(r, "exclude_tag_ids")
+	if err != nil {
+		json.BadRequest(w, r, err)
+		return
+	}
+
 	builder := h.store.NewEntryQueryBuilder(userID)
-	builder.WithEntryTagID(tagID)
+	builder.WithEntryTagIDAndSource(tagID, source)
+	for _, excludedTagID := range excludedTagIDs {
+		builder.WithoutEntryTagID(excludedTagID)
+	}
 	builder.WithoutStatus(model.EntryStatusRemoved)
 	builder.WithSorting("published_at", "DESC")
+	builder.WithOffset(offset)
+	builder.WithLimit(limit)
 	builder.WithEnclosures()
 
-	configureEntryQueryBuilder(builder, r)
+	configureFilters(builder, r)
 
 	entries, err := builder.GetEntries()
 	if err != nil {
@@ -215,6 +634,56 @@ func (h *handler) confirmAutoTag(w http.ResponseWriter, r *http.Request) {
 	json.NoContent(w, r)
 }
 
+func (h *handler) markEntryTagsReviewed(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+
+	if !h.store.EntryIDsExist(userID, []int64{entryID}) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.MarkEntryTagsReviewed(userID, entryID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+func (h *handler) countTagReviewEntries(w http.ResponseWriter, r *http.Request) {
+	count, err := h.store.CountEntriesWithPendingAutoTags(request.UserID(r))
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.OK(w, r, &tagReviewCountResponse{Count: count})
+}
+
+func (h *handler) confirmAllAutoTags(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+
+	// Confirming every auto tag can't be undone, so the client has to ask for it explicitly.
+	if !request.QueryBoolParam(r, "confirm", false) {
+		json.BadRequest(w, r, errors.New("the confirm parameter must be set to true"))
+		return
+	}
+
+	confirmed, err := h.store.ConfirmAllAutoTagsForUser(userID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	slog.Info("All auto tags confirmed",
+		slog.Int64("user_id", userID),
+		slog.Int64("auto_tags_confirmed", confirmed),
+	)
+
+	json.OK(w, r, &autoTagConfirmationResponse{Confirmed: confirmed})
+}
+
 func (h *handler) dismissAutoTag(w http.ResponseWriter, r *http.Request) {
 	userID := request.UserID(r)
 	entryID := request.RouteInt64Param(r, "entryID")
@@ -227,3 +696,58 @@ func (h *handler) dismissAutoTag(w http.ResponseWriter, r *http.Request) {
 
 	json.NoContent(w, r)
 }
+
+func (h *handler) revertTagToAuto(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	entryID := request.RouteInt64Param(r, "entryID")
+	tagID := request.RouteInt64Param(r, "tagID")
+
+	if !h.store.EntryIDsExist(userID, []int64{entryID}) || !h.store.TagIDExists(userID, tagID) {
+		json.NotFound(w, r)
+		return
+	}
+
+	if err := h.store.RevertTagToAuto(userID, entryID, tagID); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	json.NoContent(w, r)
+}
+
+// parseTimeQueryParam parses an optional RFC3339 date from the query string.
+func parseTimeQueryParam(r *http.Request, param string) (*time.Time, error) {
+	value := request.QueryStringParam(r, param, "")
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a RFC3339 date", param)
+	}
+
+	return &parsed, nil
+}
+
+// parseInt64ListQueryParam parses an optional list of IDs from the query string.
+// The parameter can be repeated or contain comma-separated values.
+func parseInt64ListQueryParam(r *http.Request, param string) ([]int64, error) {
+	var ids []int64
+	for _, value := range request.QueryStringParamList(r, param) {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			id, err := strconv.ParseInt(part, 10, 64)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("%s must be a list of positive integers", param)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package api // import "miniflux.app/v2/internal/api"
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"miniflux.app/v2/internal/http/request"
+	"miniflux.app/v2/internal/http/response"
+	"miniflux.app/v2/internal/http/response/json"
+	"miniflux.app/v2/internal/model"
+)
+
+func (h *handler) exportTagEntries(w http.ResponseWriter, r *http.Request) {
+	userID := request.UserID(r)
+	tagID := request.RouteInt64Param(r, "tagID")
+
+	format := request.QueryStringParam(r, "format", "json")
+	if format != "json" && format != "csv" {
+		json.BadRequest(w, r, errors.New("invalid format, must be json or csv"))
+		return
+	}
+
+	tag, err := h.store.TagByID(userID, tagID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	if tag == nil {
+		json.NotFound(w, r)
+		return
+	}
+
+	entryIDs, err := h.store.GetEntriesWithTag(userID, tagID)
+	if err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	exportedEntries := make([]*tagExportEntry, 0, len(entryIDs))
+	if len(entryIDs) > 0 {
+		builder := h.store.NewEntryQueryBuilder(userID)
+		builder.WithEntryIDs(entryIDs)
+		builder.WithoutStatus(model.EntryStatusRemoved)
+		builder.WithSorting("published_at", "DESC")
+		builder.WithSorting("id", "DESC")
+
+		entries, err := builder.GetEntries()
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		tagNames, err := h.store.GetTagNamesForEntries(userID, entryIDs)
+		if err != nil {
+			json.ServerError(w, r, err)
+			return
+		}
+
+		for _, entry := range entries {
+			otherTags := make([]string, 0)
+			for _, name := range tagNames[entry.ID] {
+				if !strings.EqualFold(name, tag.Name) {
+					otherTags = append(otherTags, name)
+				}
+			}
+
+			exportedEntries = append(exportedEntries, &tagExportEntry{
+				ID:          entry.ID,
+				Title:       entry.Title,
+				URL:         entry.URL,
+				FeedTitle:   entry.Feed.Title,
+				PublishedAt: entry.Date,
+				Tags:        otherTags,
+			})
+		}
+	}
+
+	if format == "json" {
+		json.OK(w, r, exportedEntries)
+		return
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write([]string{"id", "title", "url", "feed", "published_at", "tags"})
+	for _, entry := range exportedEntries {
+		writer.Write([]string{
+			fmt.Sprint(entry.ID),
+			entry.Title,
+			entry.URL,
+			entry.FeedTitle,
+			entry.PublishedAt.Format(time.RFC3339),
+			strings.Join(entry.Tags, ", "),
+		})
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		json.ServerError(w, r, err)
+		return
+	}
+
+	builder := response.New(w, r)
+	builder.WithHeader("Content-Type", "text/csv; charset=utf-8")
+	builder.WithAttachment(fmt.Sprintf("tag-%d-entries.csv", tag.ID))
+	builder.WithBody(buffer.Bytes())
+	builder.Write()
+}
@@ -0,0 +1,222 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package clustering // import "miniflux.app/v2/internal/clustering"
+
+import (
+	"sort"
+
+	"miniflux.app/v2/internal/embedding"
+)
+
+const kmeansMaxIterations = 20
+
+// All algorithms expect normalized vectors and compare them with a dot product.
+// Each algorithm returns groups of vector indexes. The first index of a group is its seed:
+// the entry the group was built around, or the one closest to the group centroid.
+
+// thresholdGroups greedily builds groups around each unassigned vector,
+// collecting the following vectors whose similarity to the seed reaches the threshold.
+func thresholdGroups(vectors [][]float32, threshold float64, minSize int) [][]int {
+	assigned := make([]bool, len(vectors))
+	var groups [][]int
+
+	for i := range vectors {
+		if assigned[i] {
+			continue
+		}
+
+		group := []int{i}
+		for j := i + 1; j < len(vectors); j++ {
+			if !assigned[j] && embedding.Dot(vectors[i], vectors[j]) >= threshold {
+				group = append(group, j)
+			}
+		}
+
+		if len(group) < minSize {
+			continue
+		}
+
+		for _, index := range group {
+			assigned[index] = true
+		}
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// kmeansGroups partitions the vectors into k groups using spherical k-means.
+// Centroids are initialized with evenly spaced vectors so the result is deterministic.
+func kmeansGroups(vectors [][]float32, k int, minSize int) [][]int {
+	if k <= 0 || len(vectors) == 0 {
+		return nil
+	}
+
+	if k > len(vectors) {
+		k = len(vectors)
+	}
+
+	centroids := make([][]float32, k)
+	for c := range centroids {
+		centroids[c] = vectors[c*len(vectors)/k]
+	}
+
+	assignments := make([]int, len(vectors))
+	for i := range assignments {
+		assignments[i] = -1
+	}
+
+	for iteration := 0; iteration < kmeansMaxIterations; iteration++ {
+		changed := false
+		for i, vector := range vectors {
+			best := nearestCentroid(vector, centroids)
+			if assignments[i] != best {
+				assignments[i] = best
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+
+		for c := range centroids {
+			var members [][]float32
+			for i, assignment := range assignments {
+				if assignment == c {
+					members = append(members, vectors[i])
+				}
+			}
+			if len(members) > 0 {
+				centroids[c] = embedding.Normalize(meanVector(members))
+			}
+		}
+	}
+
+	var groups [][]int
+	for c, centroid := range centroids {
+		var group []int
+		for i, assignment := range assignments {
+			if assignment == c {
+				group = append(group, i)
+			}
+		}
+
+		if len(group) == 0 || len(group) < minSize {
+			continue
+		}
+
+		sort.SliceStable(group, func(a, b int) bool {
+			return embedding.Dot(vectors[group[a]], centroid) > embedding.Dot(vectors[group[b]], centroid)
+		})
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// dbscanGroups groups density-connected vectors. Two vectors are neighbors when their similarity
+// reaches the threshold, and a vector is a core point when it has at least minSize-1 neighbors.
+// Vectors that are not reachable from any core point are left out as noise.
+func dbscanGroups(vectors [][]float32, threshold float64, minSize int) [][]int {
+	const noise = -1
+
+	labels := make([]int, len(vectors))
+	clusterID := 0
+	var groups [][]int
+
+	neighborsOf := func(i int) []int {
+		var neighbors []int
+		for j := range vectors {
+			if j != i && embedding.Dot(vectors[i], vectors[j]) >= threshold {
+				neighbors = append(neighbors, j)
+			}
+		}
+		return neighbors
+	}
+
+	for i := range vectors {
+		if labels[i] != 0 {
+			continue
+		}
+
+		neighbors := neighborsOf(i)
+		if len(neighbors)+1 < minSize {
+			labels[i] = noise
+			continue
+		}
+
+		clusterID++
+		labels[i] = clusterID
+		group := []int{i}
+
+		queue := neighbors
+		for len(queue) > 0 {
+			j := queue[0]
+			queue = queue[1:]
+
+			if labels[j] == noise {
+				labels[j] = clusterID
+				group = append(group, j)
+				continue
+			}
+
+			if labels[j] != 0 {
+				continue
+			}
+
+			labels[j] = clusterID
+			group = append(group, j)
+
+			if jNeighbors := neighborsOf(j); len(jNeighbors)+1 >= minSize {
+				queue = append(queue, jNeighbors...)
+			}
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// limitGroupSize keeps the seed of a group and its maxSize-1 most similar members, dropping the others.
+// A maxSize lower than or equal to zero means no limit.
+func limitGroupSize(group []int, vectors [][]float32, maxSize int) []int {
+	if maxSize <= 0 || len(group) <= maxSize {
+		return group
+	}
+
+	seed := group[0]
+	members := append([]int(nil), group[1:]...)
+	sort.SliceStable(members, func(a, b int) bool {
+		return embedding.Dot(vectors[seed], vectors[members[a]]) > embedding.Dot(vectors[seed], vectors[members[b]])
+	})
+
+	return append([]int{seed}, members[:maxSize-1]...)
+}
+
+func nearestCentroid(vector []float32, centroids [][]float32) int {
+	best := 0
+	bestSimilarity := embedding.Dot(vector, centroids[0])
+	for c := 1; c < len(centroids); c++ {
+		if similarity := embedding.Dot(vector, centroids[c]); similarity > bestSimilarity {
+			best = c
+			bestSimilarity = similarity
+		}
+	}
+	return best
+}
+
+func meanVector(vectors [][]float32) []float32 {
+	mean := make([]float32, len(vectors[0]))
+	for _, vector := range vectors {
+		for i, value := range vector {
+			mean[i] += value
+		}
+	}
+	for i := range mean {
+		mean[i] /= float32(len(vectors))
+	}
+	return mean
+}
@@ -0,0 +1,257 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clustering groups related entries of a user into clusters based on their embeddings.
+package clustering // import "miniflux.app/v2/internal/clustering"
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"miniflux.app/v2/internal/config"
+	"miniflux.app/v2/internal/embedding"
+	"miniflux.app/v2/internal/model"
+	"miniflux.app/v2/internal/storage"
+)
+
+// Algorithm identifies the strategy used to group entries.
+type Algorithm string
+
+// Supported clustering algorithms.
+const (
+	AlgorithmThreshold Algorithm = "threshold"
+	AlgorithmKMeans    Algorithm = "kmeans"
+	AlgorithmDBSCAN    Algorithm = "dbscan"
+)
+
+// Options controls how a clustering run loads, groups and persists entries.
+type Options struct {
+	Algorithm           Algorithm
+	CandidateLimit      int
+	MaxAgeDays          int
+	SimilarityThreshold float64
+	MinClusterSize      int
+	MaxClusterSize      int
+	KMeansClusters      int
+	Expiry              time.Duration
+
+	// UnclusteredOnly restricts the candidates to entries that are not a member of any cluster yet.
+	UnclusteredOnly bool
+
+	// RecentClusterWindow excludes the entries already in a cluster created within this duration,
+	// so repeated runs don't create duplicate clusters. Zero disables the exclusion.
+	RecentClusterWindow time.Duration
+
+	// MultiFeedOnly discards the groups whose entries all come from the same feed.
+	MultiFeedOnly bool
+
+	// Language selects the stopwords ignored when naming the clusters. The user language is used when empty.
+	Language string
+
+	// ExtraStopwords are ignored when naming the clusters, in addition to the stopwords of the language.
+	ExtraStopwords []string
+}
+
+// DefaultOptions returns the options used when the caller doesn't provide any.
+func DefaultOptions() *Options {
+	return &Options{
+		Algorithm:           Algorithm(config.Opts.ClusteringAlgorithm()),
+		CandidateLimit:      500,
+		MaxAgeDays:          3,
+		SimilarityThreshold: 0.8,
+		MinClusterSize:      2,
+		MaxClusterSize:      50,
+		KMeansClusters:      10,
+		Expiry:              7 * 24 * time.Hour,
+		RecentClusterWindow: 7 * 24 * time.Hour,
+		ExtraStopwords:      config.Opts.ClusteringStopwords(),
+	}
+}
+
+// RunClustering groups the recent entries of a user and persists the resulting clusters.
+func RunClustering(store *storage.Storage, userID int64, opts *Options) (model.Clusters, error) {
+	specs, err := PreviewClustering(store, userID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return store.CreateClustersBatch(userID, specs)
+}
+
+// PreviewClustering groups the recent entries of a user and returns the clusters that would be created,
+// without persisting anything.
+func PreviewClustering(store *storage.Storage, userID int64, opts *Options) ([]model.ClusterSpec, error) {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+
+	entries, err := loadCandidates(store, userID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Language == "" {
+		opts.Language = store.UserLanguage(userID)
+	}
+
+	specs, err := buildClusterSpecs(entries, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Debug("Clustering completed",
+		slog.Int64("user_id", userID),
+		slog.String("algorithm", string(opts.Algorithm)),
+		slog.Int("nb_candidates", len(entries)),
+		slog.Int("nb_clusters", len(specs)),
+	)
+
+	return specs, nil
+}
+
+// loadCandidates loads the entries to group, whatever the algorithm.
+// Unless only the unclustered entries are requested, the entries already in a recent cluster are excluded,
+// so the default run doesn't cluster them again.
+func loadCandidates(store *storage.Storage, userID int64, opts *Options) (model.Entries, error) {
+	if opts.UnclusteredOnly {
+		return store.EmbeddedUnclusteredEntries(userID, opts.CandidateLimit, opts.MaxAgeDays)
+	}
+
+	entries, err := store.GetEntriesForClustering(userID, opts.CandidateLimit, opts.MaxAgeDays)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.RecentClusterWindow <= 0 {
+		return entries, nil
+	}
+
+	entryIDs := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		entryIDs = append(entryIDs, entry.ID)
+	}
+
+	clustered, err := store.EntriesAlreadyClustered(userID, entryIDs, time.Now().Add(-opts.RecentClusterWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutEntries(entries, clustered), nil
+}
+
+// buildClusterSpecs groups the entries with the configured algorithm and describes the clusters to create.
+func buildClusterSpecs(entries model.Entries, opts *Options) ([]model.ClusterSpec, error) {
+	candidates, vectors := decodeCandidates(entries)
+
+	var groups [][]int
+	switch opts.Algorithm {
+	case AlgorithmThreshold, "":
+		groups = thresholdGroups(vectors, opts.SimilarityThreshold, opts.MinClusterSize)
+	case AlgorithmKMeans:
+		groups = kmeansGroups(vectors, opts.KMeansClusters, opts.MinClusterSize)
+	case AlgorithmDBSCAN:
+		groups = dbscanGroups(vectors, opts.SimilarityThreshold, opts.MinClusterSize)
+	default:
+		return nil, fmt.Errorf("clustering: unsupported algorithm %q", opts.Algorithm)
+	}
+
+	var expiresAt *time.Time
+	if opts.Expiry > 0 {
+		expiry := time.Now().Add(opts.Expiry)
+		expiresAt = &expiry
+	}
+
+	stopwords := StopwordsForLanguage(opts.Language, opts.ExtraStopwords)
+	specs := make([]model.ClusterSpec, 0, len(groups))
+	for _, group := range groups {
+		group = limitGroupSize(group, vectors, opts.MaxClusterSize)
+
+		members := make(model.Entries, 0, len(group))
+		entryIDs := make([]int64, 0, len(group))
+		for _, index := range group {
+			members = append(members, candidates[index])
+			entryIDs = append(entryIDs, candidates[index].ID)
+		}
+
+		if opts.MultiFeedOnly && !spansMultipleFeeds(members) {
+			continue
+		}
+
+		specs = append(specs, model.ClusterSpec{
+			Name:      GenerateClusterName(members, stopwords),
+			Source:    model.ClusterSourceAuto,
+			ExpiresAt: expiresAt,
+			EntryIDs:  entryIDs,
+		})
+	}
+
+	return specs, nil
+}
+
+// withoutEntries returns the entries whose ID is not in the excluded set.
+func withoutEntries(entries model.Entries, excluded map[int64]bool) model.Entries {
+	if len(excluded) == 0 {
+		return entries
+	}
+
+	kept := make(model.Entries, 0, len(entries))
+	for _, entry := range entries {
+		if !excluded[entry.ID] {
+			kept = append(kept, entry)
+		}
+	}
+
+	return kept
+}
+
+// spansMultipleFeeds returns true when the entries come from at least two different feeds.
+func spansMultipleFeeds(entries model.Entries) bool {
+	for _, entry := range entries[1:] {
+		if entry.FeedID != entries[0].FeedID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// decodeCandidates keeps the entries that have a usable embedding, along with their normalized vectors.
+// Vectors with a dimension different from the first decoded one are skipped.
+func decodeCandidates(entries model.Entries) (model.Entries, [][]float32) {
+	candidates := make(model.Entries, 0, len(entries))
+	vectors := make([][]float32, 0, len(entries))
+
+	for _, entry := range entries {
+		if len(entry.Embedding) == 0 {
+			continue
+		}
+
+		vector, err := embedding.Decode(entry.Embedding)
+		if err != nil {
+			slog.Warn("Skipping entry with invalid embedding",
+				slog.Int64("entry_id", entry.ID),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		if len(vectors) > 0 && len(vector) != len(vectors[0]) {
+			slog.Warn("Skipping entry with mismatched embedding dimension",
+				slog.Int64("entry_id", entry.ID),
+				slog.Int("dimension", len(vector)),
+				slog.Int("expected_dimension", len(vectors[0])),
+			)
+			continue
+		}
+
+		if !entry.EmbeddingNormalized {
+			vector = embedding.Normalize(vector)
+		}
+
+		candidates = append(candidates, entry)
+		vectors = append(vectors, vector)
+	}
+
+	return candidates, vectors
+}
@@ -0,0 +1,299 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package clustering // import "miniflux.app/v2/internal/clustering"
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"miniflux.app/v2/internal/config"
+	"miniflux.app/v2/internal/embedding"
+	"miniflux.app/v2/internal/model"
+	"miniflux.app/v2/internal/storage"
+)
+
+var testVectors = [][]float32{
+	{1, 0, 0},
+	embedding.Normalize([]float32{0.95, 0.05, 0}),
+	{0, 1, 0},
+	embedding.Normalize([]float32{0.02, 0.98, 0}),
+	{0, 0, 1},
+}
+
+func TestThresholdGroups(t *testing.T) {
+	groups := thresholdGroups(testVectors, 0.9, 2)
+	if len(groups) != 2 {
+		t.Fatalf(`Unexpected number of groups, got %d instead of 2`, len(groups))
+	}
+
+	if groups[0][0] != 0 || groups[0][1] != 1 || groups[1][0] != 2 || groups[1][1] != 3 {
+		t.Errorf(`Unexpected groups: %v`, groups)
+	}
+}
+
+func TestLimitGroupSize(t *testing.T) {
+	vectors := [][]float32{
+		{1, 0, 0},
+		embedding.Normalize([]float32{0.5, 0.5, 0}),
+		embedding.Normalize([]float32{0.95, 0.05, 0}),
+		embedding.Normalize([]float32{0.8, 0.2, 0}),
+	}
+
+	group := limitGroupSize([]int{0, 1, 2, 3}, vectors, 3)
+	if len(group) != 3 || group[0] != 0 || group[1] != 2 || group[2] != 3 {
+		t.Errorf(`Unexpected group: %v`, group)
+	}
+
+	if group := limitGroupSize([]int{0, 1, 2, 3}, vectors, 0); len(group) != 4 {
+		t.Errorf(`A zero max size should not limit the group, got %v`, group)
+	}
+}
+
+func TestKMeansGroups(t *testing.T) {
+	groups := kmeansGroups(testVectors, 3, 2)
+	if len(groups) != 2 {
+		t.Fatalf(`Unexpected number of groups, got %d instead of 2: %v`, len(groups), groups)
+	}
+
+	for _, group := range groups {
+		if len(group) != 2 {
+			t.Errorf(`Unexpected group size, got %d instead of 2: %v`, len(group), groups)
+		}
+	}
+}
+
+func TestKMeansGroupsWithMoreClustersThanVectors(t *testing.T) {
+	groups := kmeansGroups(testVectors[:2], 10, 1)
+	if len(groups) == 0 {
+		t.Fatal(`Expected at least one group`)
+	}
+}
+
+func TestDBSCANGroups(t *testing.T) {
+	groups := dbscanGroups(testVectors, 0.9, 2)
+	if len(groups) != 2 {
+		t.Fatalf(`Unexpected number of groups, got %d instead of 2: %v`, len(groups), groups)
+	}
+
+	for _, group := range groups {
+		for _, index := range group {
+			if index == 4 {
+				t.Errorf(`The isolated vector should be left out as noise: %v`, groups)
+			}
+		}
+	}
+}
+
+func TestBuildClusterSpecsWithUnsupportedAlgorithm(t *testing.T) {
+	opts := &Options{Algorithm: "unknown"}
+	if _, err := buildClusterSpecs(nil, opts); err == nil {
+		t.Fatal(`An error should be returned for an unsupported algorithm`)
+	}
+}
+
+func TestBuildClusterSpecsSkipsInvalidEmbeddings(t *testing.T) {
+	entries := model.Entries{
+		{ID: 1, Title: "Rust compiler release", Embedding: embedding.Encode([]float32{1, 0})},
+		{ID: 2, Title: "Rust compiler performance", Embedding: embedding.Encode([]float32{0.99, 0.01})},
+		{ID: 3, Title: "No embedding"},
+		{ID: 4, Title: "Invalid embedding", Embedding: []byte{1, 2, 3}},
+		{ID: 5, Title: "Other dimension", Embedding: embedding.Encode([]float32{1, 0, 0})},
+	}
+
+	specs, err := buildClusterSpecs(entries, &Options{Algorithm: AlgorithmThreshold, SimilarityThreshold: 0.9, MinClusterSize: 2})
+	if err != nil {
+		t.Fatalf(`Unexpected error: %v`, err)
+	}
+
+	if len(specs) != 1 {
+		t.Fatalf(`Unexpected number of clusters, got %d instead of 1`, len(specs))
+	}
+
+	if len(specs[0].EntryIDs) != 2 || specs[0].EntryIDs[0] != 1 || specs[0].EntryIDs[1] != 2 {
+		t.Errorf(`Unexpected entry IDs: %v`, specs[0].EntryIDs)
+	}
+
+	if specs[0].ExpiresAt != nil {
+		t.Errorf(`No expiry should be set without an expiry duration`)
+	}
+}
+
+func TestWithoutEntries(t *testing.T) {
+	entries := model.Entries{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	kept := withoutEntries(entries, map[int64]bool{2: true, 4: true})
+	if len(kept) != 2 || kept[0].ID != 1 || kept[1].ID != 3 {
+		t.Errorf(`Unexpected entries kept: %v`, kept)
+	}
+
+	if kept := withoutEntries(entries, nil); len(kept) != 3 {
+		t.Errorf(`All the entries should be kept without exclusion, got %d`, len(kept))
+	}
+}
+
+func TestRunClusteringTwiceDoesNotDuplicateClusters(t *testing.T) {
+	dsn := os.Getenv("TEST_MINIFLUX_DATABASE_URL")
+	if dsn == "" {
+		t.Skip(`Set TEST_MINIFLUX_DATABASE_URL to run the tests against a database`)
+	}
+
+	var err error
+	config.Opts, err = config.NewConfigParser().ParseEnvironmentVariables()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store := storage.NewStorage(db)
+
+	user, err := store.CreateUser(&model.UserCreationRequest{Username: fmt.Sprintf("clustering_test_user_%d", rand.Int())})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.RemoveUser(user.ID)
+
+	category, err := store.FirstCategory(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed := &model.Feed{
+		UserID:   user.ID,
+		Category: category,
+		FeedURL:  "https://example.org/feed.xml",
+		SiteURL:  "https://example.org/",
+		Title:    "Example",
+	}
+	for i, title := range []string{"Rust compiler release", "Rust compiler performance", "Election results"} {
+		feed.Entries = append(feed.Entries, &model.Entry{
+			Title: title,
+			Hash:  fmt.Sprintf("hash-%d", i),
+			URL:   fmt.Sprintf("https://example.org/%d", i),
+			Date:  time.Now(),
+		})
+	}
+	if err := store.CreateFeed(feed); err != nil {
+		t.Fatal(err)
+	}
+
+	vectors := [][]float32{{1, 0}, {0.99, 0.01}, {0, 1}}
+	for i, entry := range feed.Entries {
+		if err := store.UpdateEntryEmbedding(entry.ID, embedding.Encode(vectors[i]), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := &Options{
+		Algorithm:           AlgorithmThreshold,
+		CandidateLimit:      10,
+		MaxAgeDays:          1,
+		SimilarityThreshold: 0.9,
+		MinClusterSize:      2,
+		MaxClusterSize:      10,
+		Expiry:              time.Hour,
+		RecentClusterWindow: time.Hour,
+	}
+
+	clusters, err := RunClustering(store, user.ID, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 1 {
+		t.Fatalf(`The first run should create 1 cluster, got %d`, len(clusters))
+	}
+
+	clusters, err = RunClustering(store, user.ID, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clusters) != 0 {
+		t.Errorf(`The second run should not create any cluster, got %d`, len(clusters))
+	}
+
+	count, err := store.CountClusters(user.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf(`The user should have 1 cluster, got %d`, count)
+	}
+}
+
+func TestBuildClusterSpecsWithMultiFeedOnly(t *testing.T) {
+	entries := model.Entries{
+		{ID: 1, FeedID: 1, Title: "Weekly digest part 1", Embedding: embedding.Encode([]float32{1, 0})},
+		{ID: 2, FeedID: 1, Title: "Weekly digest part 2", Embedding: embedding.Encode([]float32{0.99, 0.01})},
+		{ID: 3, FeedID: 1, Title: "Election results", Embedding: embedding.Encode([]float32{0, 1})},
+		{ID: 4, FeedID: 2, Title: "Election results announced", Embedding: embedding.Encode([]float32{0.01, 0.99})},
+	}
+
+	opts := &Options{Algorithm: AlgorithmThreshold, SimilarityThreshold: 0.9, MinClusterSize: 2, MultiFeedOnly: true}
+	specs, err := buildClusterSpecs(entries, opts)
+	if err != nil {
+		t.Fatalf(`Unexpected error: %v`, err)
+	}
+
+	if len(specs) != 1 {
+		t.Fatalf(`Unexpected number of clusters, got %d instead of 1`, len(specs))
+	}
+
+	if specs[0].EntryIDs[0] != 3 || specs[0].EntryIDs[1] != 4 {
+		t.Errorf(`Unexpected entry IDs: %v`, specs[0].EntryIDs)
+	}
+}
+
+func TestGenerateClusterName(t *testing.T) {
+	entries := model.Entries{
+		{Title: "The new Rust compiler is faster"},
+		{Title: "Rust compiler benchmarks"},
+		{Title: "Why the Rust release matters"},
+	}
+
+	if name := GenerateClusterName(entries, StopwordsForLanguage("en_US", nil)); name != "Rust, compiler" {
+		t.Errorf(`Unexpected cluster name, got %q`, name)
+	}
+}
+
+func TestGenerateClusterNameUsesLanguageStopwords(t *testing.T) {
+	entries := model.Entries{
+		{Title: "Les nouvelles fonctions pour Rust"},
+		{Title: "Rust: les performances sont meilleures pour tous"},
+	}
+
+	if name := GenerateClusterName(entries, StopwordsForLanguage("fr_FR", nil)); name != "Rust" {
+		t.Errorf(`Unexpected cluster name, got %q`, name)
+	}
+
+	if name := GenerateClusterName(entries, StopwordsForLanguage("en_US", nil)); name == "Rust" {
+		t.Errorf(`French stopwords should not be ignored for English, got %q`, name)
+	}
+}
+
+func TestGenerateClusterNameFallsBackToFirstTitle(t *testing.T) {
+	entries := model.Entries{
+		{Title: "Apples"},
+		{Title: "Oranges"},
+	}
+
+	if name := GenerateClusterName(entries, StopwordsForLanguage("en_US", nil)); name != "Apples" {
+		t.Errorf(`Unexpected cluster name, got %q`, name)
+	}
+}
+
+func TestGenerateClusterNameIsTruncated(t *testing.T) {
+	entries := model.Entries{{Title: strings.Repeat("é", 300)}}
+
+	if name := GenerateClusterName(entries, StopwordsForLanguage("en_US", nil)); len(name) > model.ClusterNameMaxLength {
+		t.Errorf(`The cluster name should be truncated, got %d bytes`, len(name))
+	}
+}
@@ -0,0 +1,60 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package clustering // import "miniflux.app/v2/internal/clustering"
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"miniflux.app/v2/internal/model"
+)
+
+const maxClusterNameWords = 3
+
+// GenerateClusterName builds a cluster name from the most frequent meaningful words of the entry titles.
+// It falls back to the title of the first entry when no word stands out.
+func GenerateClusterName(entries model.Entries, stopwords Stopwords) string {
+	counts := make(map[string]int)
+	displayWords := make(map[string]string)
+	var order []string
+
+	for _, entry := range entries {
+		seen := make(map[string]bool)
+		for _, word := range strings.FieldsFunc(entry.Title, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			key := strings.ToLower(word)
+			if len([]rune(key)) < 3 || stopwords[key] || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if _, found := counts[key]; !found {
+				displayWords[key] = word
+				order = append(order, key)
+			}
+			counts[key]++
+		}
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+
+	var words []string
+	for _, key := range order {
+		if len(words) == maxClusterNameWords || (len(entries) > 1 && counts[key] < 2) {
+			break
+		}
+		words = append(words, displayWords[key])
+	}
+
+	name := strings.Join(words, ", ")
+	if name == "" && len(entries) > 0 {
+		return model.ClusterNameFromSeed(entries[0])
+	}
+
+	return model.TruncateClusterName(name)
+}
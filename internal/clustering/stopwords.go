@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package clustering // import "miniflux.app/v2/internal/clustering"
+
+import "strings"
+
+// defaultStopwordLanguage is used for the languages without their own stopword list.
+const defaultStopwordLanguage = "en"
+
+// Stopwords is a set of lowercase words ignored when naming clusters.
+type Stopwords map[string]bool
+
+// stopwordsByLanguage lists the words ignored when naming clusters, by ISO 639-1 language code.
+var stopwordsByLanguage = map[string]Stopwords{
+	"en": newStopwordSet(`
+		a about after all an and are as at be been but by can for from has have he her his how i if in into
+		is it its new not of on or our over says she so than that the their they this to up was we what when
+		who why will with you your`),
+	"fr": newStopwordSet(`
+		au aux avec ce ces cette dans de des du elle en est et eux il ils je la le les leur leurs lui mais
+		nous on ou par pas plus pour qu que qui sa sans se ses son sont sur un une vous votre vos été être`),
+	"de": newStopwordSet(`
+		aber als am an auch auf aus bei das dem den der des die ein eine einem einen einer eines es für
+		hat ich ihr im in ist mit nach nicht noch oder sich sie sind so über um und uns vom von vor war
+		was wie wir wird zu zum zur`),
+	"es": newStopwordSet(`
+		a al como con de del el ella en era es esta este están fue la las lo los más no nos o para pero por
+		que se sin sobre su sus también un una unos y ya`),
+	"it": newStopwordSet(`
+		a al alla anche che come con da dal dei del della delle di e gli ha il in la le lo ma nei nel nella
+		non per più se si sono su sua suo tra un una`),
+	"pt": newStopwordSet(`
+		a ao aos as com como da das de do dos e ela ele em entre era está foi mais mas na nas no nos não o
+		os para pela pelo por que se sem seu sua são também um uma`),
+	"nl": newStopwordSet(`
+		aan al als bij dat de den der die dit door een en er het hij in is je maar met naar niet nog of om
+		ons ook op over te tot uit van voor was wat we wel werd wordt ze zich zijn`),
+}
+
+func newStopwordSet(words string) Stopwords {
+	set := make(Stopwords)
+	for _, word := range strings.Fields(words) {
+		set[word] = true
+	}
+	return set
+}
+
+// StopwordsForLanguage returns the stopwords of a language (for example "fr_FR") along with the extra words.
+// The English list is used for the languages without their own list.
+func StopwordsForLanguage(language string, extraWords []string) Stopwords {
+	code, _, _ := strings.Cut(strings.ToLower(language), "_")
+	languageStopwords, found := stopwordsByLanguage[code]
+	if !found {
+		languageStopwords = stopwordsByLanguage[defaultStopwordLanguage]
+	}
+
+	stopwords := make(Stopwords, len(languageStopwords)+len(extraWords))
+	for word := range languageStopwords {
+		stopwords[word] = true
+	}
+	for _, word := range extraWords {
+		stopwords[strings.ToLower(word)] = true
+	}
+
+	return stopwords
+}
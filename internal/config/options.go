@@ -153,6 +153,24 @@ func NewConfigOptions() *configOptions {
 				RawValue:       "30",
 				ValueType:      dayType,
 			},
+			"CLUSTERING_ALGORITHM": {
+				ParsedStringValue: "threshold",
+				RawValue:          "threshold",
+				ValueType:         stringType,
+				Validator: func(rawValue string) error {
+					return validateChoices(rawValue, []string{"threshold", "kmeans", "dbscan"})
+				},
+			},
+			"CLUSTERING_MEMBERSHIP_TAGS": {
+				ParsedBoolValue: false,
+				RawValue:        "0",
+				ValueType:       boolType,
+			},
+			"CLUSTERING_STOPWORDS": {
+				ParsedStringList: []string{},
+				RawValue:         "",
+				ValueType:        stringListType,
+			},
 			"CREATE_ADMIN": {
 				ParsedBoolValue: false,
 				RawValue:        "0",
@@ -219,6 +237,22 @@ func NewConfigOptions() *configOptions {
 				RawValue:        "0",
 				ValueType:       boolType,
 			},
+			"EMBEDDING_API_KEY": {
+				ParsedStringValue: "",
+				RawValue:          "",
+				ValueType:         stringType,
+				Secret:            true,
+			},
+			"EMBEDDING_API_URL": {
+				ParsedStringValue: "",
+				RawValue:          "",
+				ValueType:         stringType,
+			},
+			"EMBEDDING_MODEL": {
+				ParsedStringValue: "",
+				RawValue:          "",
+				ValueType:         stringType,
+			},
 			"FETCH_BILIBILI_WATCH_TIME": {
 				ParsedBoolValue: false,
 				RawValue:        "0",
@@ -559,6 +593,35 @@ func NewConfigOptions() *configOptions {
 					return validateGreaterOrEqualThan(rawValue, 1)
 				},
 			},
+			"TAGS_AUTO_EXPIRY_DAYS": {
+				ParsedIntValue: 0,
+				RawValue:       "0",
+				ValueType:      intType,
+				Validator: func(rawValue string) error {
+					return validateGreaterOrEqualThan(rawValue, 0)
+				},
+			},
+			"TAGS_MAX_AUTO_PER_ENTRY": {
+				ParsedIntValue: 20,
+				RawValue:       "20",
+				ValueType:      intType,
+				Validator: func(rawValue string) error {
+					return validateGreaterOrEqualThan(rawValue, 1)
+				},
+			},
+			"TAGS_MAX_PER_ENTRY": {
+				ParsedIntValue: 50,
+				RawValue:       "50",
+				ValueType:      intType,
+				Validator: func(rawValue string) error {
+					return validateGreaterOrEqualThan(rawValue, 1)
+				},
+			},
+			"TAGS_SORT_COLLATION": {
+				ParsedStringValue: "",
+				RawValue:          "",
+				ValueType:         stringType,
+			},
 			"WATCHDOG": {
 				ParsedBoolValue: true,
 				RawValue:        "1",
@@ -652,6 +715,18 @@ func (c *configOptions) CleanupRemoveSessionsInterval() time.Duration {
 	return c.options["CLEANUP_REMOVE_SESSIONS_DAYS"].ParsedDuration
 }
 
+func (c *configOptions) ClusteringAlgorithm() string {
+	return c.options["CLUSTERING_ALGORITHM"].ParsedStringValue
+}
+
+func (c *configOptions) ClusteringMembershipTags() bool {
+	return c.options["CLUSTERING_MEMBERSHIP_TAGS"].ParsedBoolValue
+}
+
+func (c *configOptions) ClusteringStopwords() []string {
+	return c.options["CLUSTERING_STOPWORDS"].ParsedStringList
+}
+
 func (c *configOptions) CreateAdmin() bool {
 	return c.options["CREATE_ADMIN"].ParsedBoolValue
 }
@@ -688,6 +763,18 @@ func (c *configOptions) DisableSchedulerService() bool {
 	return c.options["DISABLE_SCHEDULER_SERVICE"].ParsedBoolValue
 }
 
+func (c *configOptions) EmbeddingAPIKey() string {
+	return c.options["EMBEDDING_API_KEY"].ParsedStringValue
+}
+
+func (c *configOptions) EmbeddingAPIURL() string {
+	return c.options["EMBEDDING_API_URL"].ParsedStringValue
+}
+
+func (c *configOptions) EmbeddingModel() string {
+	return c.options["EMBEDDING_MODEL"].ParsedStringValue
+}
+
 func (c *configOptions) FetchBilibiliWatchTime() bool {
 	return c.options["FETCH_BILIBILI_WATCH_TIME"].ParsedBoolValue
 }
@@ -720,6 +807,10 @@ func (c *configOptions) HasAPI() bool {
 	return !c.options["DISABLE_API"].ParsedBoolValue
 }
 
+func (c *configOptions) HasEmbeddingProvider() bool {
+	return c.EmbeddingAPIURL() != ""
+}
+
 func (c *configOptions) HasHTTPService() bool {
 	return !c.options["DISABLE_HTTP_SERVICE"].ParsedBoolValue
 }
@@ -953,6 +1044,22 @@ func (c *configOptions) SchedulerRoundRobinMinInterval() time.Duration {
 	return c.options["SCHEDULER_ROUND_ROBIN_MIN_INTERVAL"].ParsedDuration
 }
 
+func (c *configOptions) TagsAutoExpiryDays() int {
+	return c.options["TAGS_AUTO_EXPIRY_DAYS"].ParsedIntValue
+}
+
+func (c *configOptions) TagsMaxAutoPerEntry() int {
+	return c.options["TAGS_MAX_AUTO_PER_ENTRY"].ParsedIntValue
+}
+
+func (c *configOptions) TagsMaxPerEntry() int {
+	return c.options["TAGS_MAX_PER_ENTRY"].ParsedIntValue
+}
+
+func (c *configOptions) TagsSortCollation() string {
+	return c.options["TAGS_SORT_COLLATION"].ParsedStringValue
+}
+
 func (c *configOptions) Watchdog() bool {
 	return c.options["WATCHDOG"].ParsedBoolValue
 }
@@ -289,6 +289,156 @@ func TestCleanupArchiveBatchSizeOptionParsing(t *testing.T) {
 	}
 }
 
+func TestTagsAutoExpiryDaysOptionParsing(t *testing.T) {
+	configParser := NewConfigParser()
+
+	if configParser.options.TagsAutoExpiryDays() != 0 {
+		t.Fatalf("Expected TAGS_AUTO_EXPIRY_DAYS to be 0 by default")
+	}
+
+	if err := configParser.parseLines([]string{"TAGS_AUTO_EXPIRY_DAYS=30"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if configParser.options.TagsAutoExpiryDays() != 30 {
+		t.Fatalf("Expected TAGS_AUTO_EXPIRY_DAYS to be 30")
+	}
+
+	if err := configParser.parseLines([]string{"TAGS_AUTO_EXPIRY_DAYS=-1"}); err == nil {
+		t.Fatalf("Expected error for negative TAGS_AUTO_EXPIRY_DAYS value")
+	}
+}
+
+func TestTagsMaxPerEntryOptionParsing(t *testing.T) {
+	configParser := NewConfigParser()
+
+	if configParser.options.TagsMaxPerEntry() != 50 {
+		t.Fatalf("Expected TAGS_MAX_PER_ENTRY to be 50 by default")
+	}
+
+	if configParser.options.TagsMaxAutoPerEntry() != 20 {
+		t.Fatalf("Expected TAGS_MAX_AUTO_PER_ENTRY to be 20 by default")
+	}
+
+	if err := configParser.parseLines([]string{"TAGS_MAX_PER_ENTRY=10", "TAGS_MAX_AUTO_PER_ENTRY=5"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if configParser.options.TagsMaxPerEntry() != 10 {
+		t.Fatalf("Expected TAGS_MAX_PER_ENTRY to be 10")
+	}
+
+	if configParser.options.TagsMaxAutoPerEntry() != 5 {
+		t.Fatalf("Expected TAGS_MAX_AUTO_PER_ENTRY to be 5")
+	}
+
+	if err := configParser.parseLines([]string{"TAGS_MAX_PER_ENTRY=0"}); err == nil {
+		t.Fatalf("Expected error for TAGS_MAX_PER_ENTRY=0")
+	}
+}
+
+func TestTagsSortCollationOptionParsing(t *testing.T) {
+	configParser := NewConfigParser()
+
+	if configParser.options.TagsSortCollation() != "" {
+		t.Fatalf("Expected TAGS_SORT_COLLATION to be empty by default")
+	}
+
+	if err := configParser.parseLines([]string{"TAGS_SORT_COLLATION=und-x-icu"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if configParser.options.TagsSortCollation() != "und-x-icu" {
+		t.Fatalf("Expected TAGS_SORT_COLLATION to be 'und-x-icu'")
+	}
+}
+
+func TestClusteringAlgorithmOptionParsing(t *testing.T) {
+	configParser := NewConfigParser()
+
+	if configParser.options.ClusteringAlgorithm() != "threshold" {
+		t.Fatalf("Expected CLUSTERING_ALGORITHM to be 'threshold' by default")
+	}
+
+	if err := configParser.parseLines([]string{"CLUSTERING_ALGORITHM=dbscan"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if configParser.options.ClusteringAlgorithm() != "dbscan" {
+		t.Fatalf("Expected CLUSTERING_ALGORITHM to be 'dbscan'")
+	}
+
+	if err := configParser.parseLines([]string{"CLUSTERING_ALGORITHM=invalid"}); err == nil {
+		t.Fatalf("Expected error for invalid CLUSTERING_ALGORITHM value")
+	}
+}
+
+func TestClusteringMembershipTagsOptionParsing(t *testing.T) {
+	configParser := NewConfigParser()
+
+	if configParser.options.ClusteringMembershipTags() {
+		t.Fatalf("Expected CLUSTERING_MEMBERSHIP_TAGS to be disabled by default")
+	}
+
+	if err := configParser.parseLines([]string{"CLUSTERING_MEMBERSHIP_TAGS=1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !configParser.options.ClusteringMembershipTags() {
+		t.Fatalf("Expected CLUSTERING_MEMBERSHIP_TAGS to be enabled")
+	}
+}
+
+func TestClusteringStopwordsOptionParsing(t *testing.T) {
+	configParser := NewConfigParser()
+
+	if len(configParser.options.ClusteringStopwords()) != 0 {
+		t.Fatalf("Expected CLUSTERING_STOPWORDS to be empty by default")
+	}
+
+	if err := configParser.parseLines([]string{"CLUSTERING_STOPWORDS=breaking, update"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	stopwords := configParser.options.ClusteringStopwords()
+	if len(stopwords) != 2 || stopwords[0] != "breaking" || stopwords[1] != "update" {
+		t.Fatalf("Expected CLUSTERING_STOPWORDS to be 'breaking' and 'update', got %v", stopwords)
+	}
+}
+
+func TestEmbeddingProviderOptionParsing(t *testing.T) {
+	configParser := NewConfigParser()
+
+	if configParser.options.HasEmbeddingProvider() {
+		t.Fatalf("Expected no embedding provider by default")
+	}
+
+	lines := []string{
+		"EMBEDDING_API_URL=http://localhost:11434/v1",
+		"EMBEDDING_API_KEY=secret",
+		"EMBEDDING_MODEL=nomic-embed-text",
+	}
+	if err := configParser.parseLines(lines); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !configParser.options.HasEmbeddingProvider() {
+		t.Fatalf("Expected an embedding provider")
+	}
+
+	if configParser.options.EmbeddingAPIURL() != "http://localhost:11434/v1" {
+		t.Fatalf("Expected EMBEDDING_API_URL to be 'http://localhost:11434/v1', got %q", configParser.options.EmbeddingAPIURL())
+	}
+
+	if configParser.options.EmbeddingAPIKey() != "secret" {
+		t.Fatalf("Expected EMBEDDING_API_KEY to be 'secret', got %q", configParser.options.EmbeddingAPIKey())
+	}
+
+	if configParser.options.EmbeddingModel() != "nomic-embed-text" {
+		t.Fatalf("Expected EMBEDDING_MODEL to be 'nomic-embed-text', got %q", configParser.options.EmbeddingModel())
+	}
+}
+
 func TestCreateAdminOptionParsing(t *testing.T) {
 	configParser := NewConfigParser()
 
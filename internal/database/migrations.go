@@ -1454,4 +1454,166 @@ var migrations = [...]func(tx *sql.Tx) error{
 		_, err = tx.Exec(sql)
 		return err
 	},
+	// Lintile: Add description column to tags
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE tags ADD COLUMN description TEXT;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add pinned flag to tags
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE tags ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT false;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add flag recording whether the stored embedding is L2-normalized
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE entries ADD COLUMN embedding_normalized BOOLEAN NOT NULL DEFAULT false;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add per-feed toggle to disable auto-tagging
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE feeds ADD COLUMN disable_auto_tagging BOOLEAN NOT NULL DEFAULT false;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add embedding_backfill_state table to resume embedding backfills
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			CREATE TABLE embedding_backfill_state (
+				user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+				last_entry_id BIGINT NOT NULL,
+				last_published_at TIMESTAMP WITH TIME ZONE NOT NULL,
+				updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+			);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add composite indexes for tag-entry lookups
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			CREATE INDEX entry_tags_tag_id_entry_id_idx ON entry_tags(tag_id, entry_id);
+			DROP INDEX IF EXISTS entry_tags_tag_id_idx;
+			CREATE INDEX entries_user_id_published_at_idx ON entries(user_id, published_at);
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add summary_format column to entries
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE entries ADD COLUMN summary_format TEXT;
+			UPDATE entries SET summary_format = 'paragraph' WHERE summary IS NOT NULL;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add expires_at column to tags for expiring auto tags
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE tags ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add centroid column to clusters
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE clusters ADD COLUMN centroid BYTEA;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add summary_content_hash column to entries
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE entries ADD COLUMN summary_content_hash TEXT;
+			UPDATE entries SET summary_content_hash = hash WHERE summary IS NOT NULL;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add optional category scope to tags
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			WITH ranked AS (
+				SELECT id, MIN(id) OVER (PARTITION BY user_id, lower(name)) AS keep_id FROM tags
+			)
+			INSERT INTO entry_tags (entry_id, tag_id, source, created_at)
+			SELECT et.entry_id, ranked.keep_id, et.source, et.created_at
+			FROM entry_tags et
+			JOIN ranked ON ranked.id = et.tag_id
+			WHERE ranked.id <> ranked.keep_id
+			ON CONFLICT (entry_id, tag_id) DO NOTHING;
+
+			DELETE FROM tags t
+			USING (SELECT id, MIN(id) OVER (PARTITION BY user_id, lower(name)) AS keep_id FROM tags) ranked
+			WHERE t.id = ranked.id AND ranked.id <> ranked.keep_id;
+
+			ALTER TABLE tags ADD COLUMN category_id INT REFERENCES categories(id) ON DELETE CASCADE;
+			ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_user_id_name_key;
+			CREATE UNIQUE INDEX tags_user_id_category_id_name_idx ON tags (user_id, COALESCE(category_id, 0), lower(name));
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add a tag version counter to users
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE users ADD COLUMN tag_version BIGINT NOT NULL DEFAULT 0;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add the date a user last viewed their clusters
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE users ADD COLUMN clusters_viewed_at TIMESTAMP WITH TIME ZONE;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add the embedding model name to entries
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE entries ADD COLUMN embedding_model TEXT;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add the tag applied to starred entries to users
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE users ADD COLUMN starred_tag_name TEXT NOT NULL DEFAULT '';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add the tags review date to entries
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			ALTER TABLE entries ADD COLUMN tags_reviewed_at TIMESTAMP WITH TIME ZONE;
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
+	// Lintile: Add the source of clusters
+	func(tx *sql.Tx) (err error) {
+		sql := `
+			CREATE TYPE cluster_source AS ENUM ('manual', 'auto');
+			ALTER TABLE clusters ADD COLUMN source cluster_source NOT NULL DEFAULT 'manual';
+		`
+		_, err = tx.Exec(sql)
+		return err
+	},
 }
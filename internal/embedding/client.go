@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package embedding // import "miniflux.app/v2/internal/embedding"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"miniflux.app/v2/internal/urllib"
+	"miniflux.app/v2/internal/version"
+)
+
+const defaultClientTimeout = 30 * time.Second
+
+// Client is an Embedder using an OpenAI-compatible embeddings API, as provided by OpenAI, Ollama or llama.cpp.
+type Client struct {
+	baseURL string
+	apiKey  string
+	model   string
+}
+
+// NewClient returns a client for the embeddings API at the given base URL.
+// The API key is optional, local providers usually don't require one.
+func NewClient(baseURL, apiKey, model string) *Client {
+	return &Client{baseURL: baseURL, apiKey: apiKey, model: model}
+}
+
+// Embed computes the embedding of the text with the configured model.
+func (c *Client) Embed(ctx context.Context, text string) ([]float32, error) {
+	if c.baseURL == "" {
+		return nil, errors.New("embedding: missing API base URL")
+	}
+
+	apiEndpoint, err := urllib.JoinBaseURLAndPath(c.baseURL, "/embeddings")
+	if err != nil {
+		return nil, fmt.Errorf("embedding: invalid API endpoint: %v", err)
+	}
+
+	requestBody, err := json.Marshal(&embeddingsRequest{Model: c.model, Input: text})
+	if err != nil {
+		return nil, fmt.Errorf("embedding: unable to encode request body: %v", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, apiEndpoint, bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("embedding: unable to create request: %v", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", "Miniflux/"+version.Version)
+	if c.apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	httpClient := &http.Client{Timeout: defaultClientTimeout}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("embedding: unable to send request: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 400 {
+		return nil, fmt.Errorf("embedding: unable to compute embedding: url=%s status=%d", apiEndpoint, response.StatusCode)
+	}
+
+	var embeddingsResponse embeddingsResponse
+	if err := json.NewDecoder(response.Body).Decode(&embeddingsResponse); err != nil {
+		return nil, fmt.Errorf("embedding: unable to decode response: %v", err)
+	}
+
+	if len(embeddingsResponse.Data) == 0 || len(embeddingsResponse.Data[0].Embedding) == 0 {
+		return nil, errors.New("embedding: the response doesn't contain any embedding")
+	}
+
+	return embeddingsResponse.Data[0].Embedding, nil
+}
+
+type embeddingsRequest struct {
+	Model string `json:"model,omitempty"`
+	Input string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
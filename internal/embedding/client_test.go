@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package embedding // import "miniflux.app/v2/internal/embedding"
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientEmbed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf(`Unexpected path %q`, r.URL.Path)
+		}
+
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-api-key" {
+			t.Errorf(`Unexpected Authorization header %q`, auth)
+		}
+
+		var request embeddingsRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf(`Unable to decode the request: %v`, err)
+		}
+
+		if request.Model != "test-model" || request.Input != "rust compiler" {
+			t.Errorf(`Unexpected request: %+v`, request)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"embedding": [0.5, -1, 2]}]}`))
+	}))
+	defer server.Close()
+
+	vector, err := NewClient(server.URL+"/v1", "test-api-key", "test-model").Embed(context.Background(), "rust compiler")
+	if err != nil {
+		t.Fatalf(`Unexpected error: %v`, err)
+	}
+
+	if len(vector) != 3 || vector[0] != 0.5 || vector[1] != -1 || vector[2] != 2 {
+		t.Errorf(`Unexpected vector: %v`, vector)
+	}
+}
+
+func TestClientEmbedWithErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL, "", "").Embed(context.Background(), "query"); err == nil {
+		t.Fatal(`An error should be returned for an error response`)
+	}
+}
+
+func TestClientEmbedWithEmptyResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL, "", "").Embed(context.Background(), "query"); err == nil {
+		t.Fatal(`An error should be returned when no embedding is returned`)
+	}
+}
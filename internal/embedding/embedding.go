@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package embedding provides functions to serialize and compare entry embeddings.
+package embedding // import "miniflux.app/v2/internal/embedding"
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Embedder computes the embedding of a text, for example a search query.
+type Embedder interface {
+	Embed(ctx context.Context, text string) ([]float32, error)
+}
+
+// Encode serializes a vector as a sequence of little-endian IEEE 754 float32 values.
+func Encode(vector []float32) []byte {
+	data := make([]byte, len(vector)*4)
+	for i, value := range vector {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(value))
+	}
+	return data
+}
+
+// Decode converts a serialized embedding back to a vector.
+func Decode(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("embedding: invalid byte length %d, expected a multiple of 4", len(data))
+	}
+
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return vector, nil
+}
+
+// CosineSimilarity returns the cosine similarity of two vectors.
+// It returns 0 when the vectors have different dimensions or when one of them is a zero vector.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Normalize returns a copy of the vector scaled to unit length.
+// A zero vector is returned unchanged since it has no direction.
+func Normalize(vector []float32) []float32 {
+	var norm float64
+	for _, value := range vector {
+		norm += float64(value) * float64(value)
+	}
+
+	normalized := make([]float32, len(vector))
+	copy(normalized, vector)
+
+	if norm == 0 {
+		return normalized
+	}
+
+	norm = math.Sqrt(norm)
+	for i := range normalized {
+		normalized[i] = float32(float64(normalized[i]) / norm)
+	}
+	return normalized
+}
+
+// Dot returns the dot product of two vectors, which equals their cosine similarity when both are normalized.
+// It returns 0 when the vectors have different dimensions.
+func Dot(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package embedding // import "miniflux.app/v2/internal/embedding"
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	vector := []float32{0.5, -1.25, 3, 0}
+
+	data := Encode(vector)
+	if len(data) != 16 {
+		t.Fatalf(`Unexpected byte length, got %d instead of 16`, len(data))
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf(`Unexpected error: %v`, err)
+	}
+
+	if len(decoded) != len(vector) {
+		t.Fatalf(`Unexpected vector length, got %d instead of %d`, len(decoded), len(vector))
+	}
+
+	for i := range vector {
+		if decoded[i] != vector[i] {
+			t.Errorf(`Unexpected value at index %d, got %v instead of %v`, i, decoded[i], vector[i])
+		}
+	}
+}
+
+func TestDecodeWithInvalidLength(t *testing.T) {
+	if _, err := Decode([]byte{1, 2, 3}); err == nil {
+		t.Error(`A byte length that is not a multiple of 4 should generate an error`)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	scenarios := []struct {
+		a, b     []float32
+		expected float64
+	}{
+		{[]float32{1, 0}, []float32{1, 0}, 1},
+		{[]float32{1, 0}, []float32{0, 1}, 0},
+		{[]float32{1, 0}, []float32{-1, 0}, -1},
+		{[]float32{1, 1}, []float32{2, 2}, 1},
+		{[]float32{1, 0}, []float32{1, 0, 0}, 0},
+		{[]float32{0, 0}, []float32{1, 0}, 0},
+	}
+
+	for _, scenario := range scenarios {
+		result := CosineSimilarity(scenario.a, scenario.b)
+		if math.Abs(result-scenario.expected) > 1e-9 {
+			t.Errorf(`Unexpected similarity for %v and %v, got %v instead of %v`, scenario.a, scenario.b, result, scenario.expected)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	vector := []float32{3, 4}
+	normalized := Normalize(vector)
+
+	if math.Abs(float64(normalized[0])-0.6) > 1e-6 || math.Abs(float64(normalized[1])-0.8) > 1e-6 {
+		t.Errorf(`Unexpected normalized vector: %v`, normalized)
+	}
+
+	if vector[0] != 3 || vector[1] != 4 {
+		t.Errorf(`The original vector should not be modified: %v`, vector)
+	}
+
+	if zero := Normalize([]float32{0, 0}); zero[0] != 0 || zero[1] != 0 {
+		t.Errorf(`A zero vector should be returned unchanged: %v`, zero)
+	}
+}
+
+func TestDotOfNormalizedVectorsMatchesCosineSimilarity(t *testing.T) {
+	a := []float32{1, 2, 3}
+	b := []float32{-2, 0.5, 4}
+
+	dot := Dot(Normalize(a), Normalize(b))
+	if math.Abs(dot-CosineSimilarity(a, b)) > 1e-6 {
+		t.Errorf(`Unexpected dot product, got %v instead of %v`, dot, CosineSimilarity(a, b))
+	}
+
+	if Dot([]float32{1}, []float32{1, 0}) != 0 {
+		t.Error(`Vectors with different dimensions should have a dot product of 0`)
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"miniflux.app/v2/internal/api"
 	"miniflux.app/v2/internal/config"
+	"miniflux.app/v2/internal/embedding"
 	"miniflux.app/v2/internal/fever"
 	"miniflux.app/v2/internal/googlereader"
 	"miniflux.app/v2/internal/http/request"
@@ -244,7 +245,12 @@ func setupHandler(store *storage.Storage, pool *worker.Pool) *mux.Router {
 	fever.Serve(subrouter, store)
 	googlereader.Serve(subrouter, store)
 	if config.Opts.HasAPI() {
-		api.Serve(subrouter, store, pool)
+		// The semantic search endpoint is only registered when an embedding provider is configured.
+		var embedder embedding.Embedder
+		if config.Opts.HasEmbeddingProvider() {
+			embedder = embedding.NewClient(config.Opts.EmbeddingAPIURL(), config.Opts.EmbeddingAPIKey(), config.Opts.EmbeddingModel())
+		}
+		api.Serve(subrouter, store, pool, embedder)
 	}
 	ui.Serve(subrouter, store, pool)
 
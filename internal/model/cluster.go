@@ -5,18 +5,39 @@ package model // import "miniflux.app/v2/internal/model"
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
+// Cluster sort orders.
+const (
+	ClusterSortCreatedAt = "created_at"
+	ClusterSortFreshness = "freshness"
+	ClusterSortUnread    = "unread"
+)
+
+// Cluster sources.
+const (
+	ClusterSourceManual = "manual"
+	ClusterSourceAuto   = "auto"
+)
+
+// ClusterNameMaxLength is the maximum length of a cluster name, in bytes.
+const ClusterNameMaxLength = 255
+
 // Cluster represents a group of related entries.
+// Source tells whether the cluster was created by the user or by the clustering job.
 type Cluster struct {
-	ID         int64      `json:"id"`
-	UserID     int64      `json:"user_id"`
-	Name       string     `json:"name"`
-	CreatedAt  time.Time  `json:"created_at"`
-	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
-	EntryCount *int       `json:"entry_count,omitempty"`
-	Entries    Entries    `json:"entries,omitempty"`
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Name        string     `json:"name"`
+	Source      string     `json:"source"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	EntryCount  *int       `json:"entry_count,omitempty"`
+	UnreadCount *int       `json:"unread_count,omitempty"`
+	FeedCount   *int       `json:"feed_count,omitempty"`
+	Entries     Entries    `json:"entries,omitempty"`
 }
 
 func (c *Cluster) String() string {
@@ -26,8 +47,82 @@ func (c *Cluster) String() string {
 // Clusters represents a list of clusters.
 type Clusters []*Cluster
 
+// ClusterNameFromSeed names a cluster after the title of its seed entry.
+func ClusterNameFromSeed(entry *Entry) string {
+	if entry == nil {
+		return ""
+	}
+
+	return TruncateClusterName(strings.TrimSpace(entry.Title))
+}
+
+// TruncateClusterName shortens a name to ClusterNameMaxLength bytes without splitting a character.
+func TruncateClusterName(name string) string {
+	if len(name) <= ClusterNameMaxLength {
+		return name
+	}
+
+	runes := []rune(name)
+	for len(string(runes)) > ClusterNameMaxLength {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes)
+}
+
+// Timeline bucket sizes.
+const (
+	TimelineBucketDay  = "day"
+	TimelineBucketWeek = "week"
+)
+
+// TimelinePoint represents a number of occurrences during the day or week starting at the given date.
+type TimelinePoint struct {
+	Date  time.Time `json:"date"`
+	Count int       `json:"count"`
+}
+
 // ClusterEntry represents the association between a cluster and an entry.
 type ClusterEntry struct {
 	ClusterID int64 `json:"cluster_id"`
 	EntryID   int64 `json:"entry_id"`
 }
+
+// ClusterSpec describes a cluster to create along with its member entries.
+// When CollapseDuplicates is set, only one of the entries sharing the same content hash is kept.
+// An empty Source means the cluster is created manually.
+type ClusterSpec struct {
+	Name               string     `json:"name"`
+	Source             string     `json:"source"`
+	ExpiresAt          *time.Time `json:"expires_at"`
+	EntryIDs           []int64    `json:"entry_ids"`
+	CollapseDuplicates bool       `json:"collapse_duplicates"`
+}
+
+// ClusterEntriesRequest represents a request to add entries to an existing cluster.
+type ClusterEntriesRequest struct {
+	EntryIDs           []int64 `json:"entry_ids"`
+	CollapseDuplicates bool    `json:"collapse_duplicates"`
+}
+
+// ClusterExpiryRequest represents a request to change the expiration date of a cluster.
+// A nil ExpiresAt makes the cluster permanent.
+type ClusterExpiryRequest struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ClusterTagRequest represents a request to tag all the entries of a cluster.
+type ClusterTagRequest struct {
+	TagName string `json:"tag_name"`
+	Source  string `json:"source,omitempty"`
+}
+
+// ClusterConversionRequest represents a request to turn a cluster into a tag applied to its entries.
+type ClusterConversionRequest struct {
+	TagName       string `json:"tag_name"`
+	DeleteCluster bool   `json:"delete_cluster"`
+}
+
+// ClusterBatchCreationRequest represents a request to create several clusters at once.
+type ClusterBatchCreationRequest struct {
+	Clusters []ClusterSpec `json:"clusters"`
+}
@@ -0,0 +1,23 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package model // import "miniflux.app/v2/internal/model"
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClusterNameFromSeed(t *testing.T) {
+	if name := ClusterNameFromSeed(&Entry{Title: "  Rust 2.0 released  "}); name != "Rust 2.0 released" {
+		t.Errorf(`Unexpected cluster name, got %q`, name)
+	}
+
+	if name := ClusterNameFromSeed(&Entry{Title: strings.Repeat("a", 300)}); len(name) != ClusterNameMaxLength {
+		t.Errorf(`The cluster name should be truncated, got %d bytes`, len(name))
+	}
+
+	if name := ClusterNameFromSeed(nil); name != "" {
+		t.Errorf(`A nil entry should produce an empty name, got %q`, name)
+	}
+}
@@ -0,0 +1,15 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package model // import "miniflux.app/v2/internal/model"
+
+import "time"
+
+// EmbeddingBackfillState records the last entry processed by the embedding backfill of a user,
+// so the backfill can resume where it stopped instead of starting again from the newest entries.
+type EmbeddingBackfillState struct {
+	UserID          int64     `json:"user_id"`
+	LastEntryID     int64     `json:"last_entry_id"`
+	LastPublishedAt time.Time `json:"last_published_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
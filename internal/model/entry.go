@@ -16,6 +16,12 @@ const (
 	DefaultSortingDirection = "asc"
 )
 
+// Entry summary formats.
+const (
+	SummaryFormatParagraph = "paragraph"
+	SummaryFormatBullets   = "bullets"
+)
+
 // Entry represents a feed item in the system.
 type Entry struct {
 	ID          int64         `json:"id"`
@@ -39,11 +45,19 @@ type Entry struct {
 	Tags        []string      `json:"tags"`
 
 	// AI-powered features (Lintile)
-	Summary           string     `json:"summary,omitempty"`
-	SummarizedAt      *time.Time `json:"summarized_at,omitempty"`
-	Embedding         []byte     `json:"-"` // Not exposed via API
-	FullTextFetchedAt *time.Time `json:"full_text_fetched_at,omitempty"`
-	EntryTags         EntryTags  `json:"entry_tags,omitempty"`
+	Summary             string     `json:"summary,omitempty"`
+	SummaryFormat       string     `json:"summary_format,omitempty"`
+	SummarizedAt        *time.Time `json:"summarized_at,omitempty"`
+	Embedding           []byte     `json:"-"` // Not exposed via API
+	EmbeddingNormalized bool       `json:"-"`
+	FullTextFetchedAt   *time.Time `json:"full_text_fetched_at,omitempty"`
+	EntryTags           EntryTags  `json:"entry_tags,omitempty"`
+}
+
+// ScoredEntry is an entry along with its similarity score to a reference vector or entry.
+type ScoredEntry struct {
+	Entry *Entry  `json:"entry"`
+	Score float64 `json:"score"`
 }
 
 func NewEntry() *Entry {
@@ -82,6 +96,12 @@ type EntriesStatusUpdateRequest struct {
 	Status   string  `json:"status"`
 }
 
+// EntryEmbeddingRequest represents a request to import an embedding computed outside of the application.
+type EntryEmbeddingRequest struct {
+	Vector []float32 `json:"vector"`
+	Model  string    `json:"model"`
+}
+
 // EntryUpdateRequest represents a request to update an entry.
 type EntryUpdateRequest struct {
 	Title   *string `json:"title"`
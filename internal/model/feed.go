@@ -47,6 +47,7 @@ type Feed struct {
 	Password                    string    `json:"password"`
 	Disabled                    bool      `json:"disabled"`
 	NoMediaPlayer               bool      `json:"no_media_player"`
+	DisableAutoTagging          bool      `json:"disable_auto_tagging"`
 	IgnoreHTTPCache             bool      `json:"ignore_http_cache"`
 	AllowSelfSignedCertificates bool      `json:"allow_self_signed_certificates"`
 	FetchViaProxy               bool      `json:"fetch_via_proxy"`
@@ -158,6 +159,7 @@ type FeedCreationRequest struct {
 	Crawler                     bool   `json:"crawler"`
 	Disabled                    bool   `json:"disabled"`
 	NoMediaPlayer               bool   `json:"no_media_player"`
+	DisableAutoTagging          bool   `json:"disable_auto_tagging"`
 	IgnoreHTTPCache             bool   `json:"ignore_http_cache"`
 	AllowSelfSignedCertificates bool   `json:"allow_self_signed_certificates"`
 	FetchViaProxy               bool   `json:"fetch_via_proxy"`
@@ -202,6 +204,7 @@ type FeedModificationRequest struct {
 	CategoryID                  *int64  `json:"category_id"`
 	Disabled                    *bool   `json:"disabled"`
 	NoMediaPlayer               *bool   `json:"no_media_player"`
+	DisableAutoTagging          *bool   `json:"disable_auto_tagging"`
 	IgnoreHTTPCache             *bool   `json:"ignore_http_cache"`
 	AllowSelfSignedCertificates *bool   `json:"allow_self_signed_certificates"`
 	FetchViaProxy               *bool   `json:"fetch_via_proxy"`
@@ -288,6 +291,10 @@ func (f *FeedModificationRequest) Patch(feed *Feed) {
 		feed.NoMediaPlayer = *f.NoMediaPlayer
 	}
 
+	if f.DisableAutoTagging != nil {
+		feed.DisableAutoTagging = *f.DisableAutoTagging
+	}
+
 	if f.IgnoreHTTPCache != nil {
 		feed.IgnoreHTTPCache = *f.IgnoreHTTPCache
 	}
@@ -315,3 +322,10 @@ func (f *FeedModificationRequest) Patch(feed *Feed) {
 
 // Feeds is a list of feed
 type Feeds []*Feed
+
+// FeedCount represents a feed along with a number of entries.
+type FeedCount struct {
+	FeedID int64  `json:"feed_id"`
+	Title  string `json:"title"`
+	Count  int    `json:"count"`
+}
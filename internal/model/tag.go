@@ -16,11 +16,15 @@ const (
 
 // Tag represents a user-defined tag that can be applied to entries.
 type Tag struct {
-	ID         int64     `json:"id"`
-	UserID     int64     `json:"user_id"`
-	Name       string    `json:"name"`
-	CreatedAt  time.Time `json:"created_at"`
-	EntryCount *int      `json:"entry_count,omitempty"`
+	ID          int64      `json:"id"`
+	UserID      int64      `json:"user_id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description,omitempty"`
+	Pinned      bool       `json:"pinned"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CategoryID  *int64     `json:"category_id,omitempty"`
+	EntryCount  *int       `json:"entry_count,omitempty"`
 }
 
 func (t *Tag) String() string {
@@ -32,27 +36,69 @@ type Tags []*Tag
 
 // TagCreationRequest represents a request to create a new tag.
 type TagCreationRequest struct {
-	Name string `json:"name"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	CategoryID  *int64  `json:"category_id"`
 }
 
 // TagModificationRequest represents a request to modify a tag.
 type TagModificationRequest struct {
-	Name *string `json:"name"`
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Pinned      *bool   `json:"pinned"`
 }
 
 func (t *TagModificationRequest) Patch(tag *Tag) {
 	if t.Name != nil {
 		tag.Name = *t.Name
 	}
+
+	if t.Description != nil {
+		if *t.Description == "" {
+			tag.Description = nil
+		} else {
+			tag.Description = t.Description
+		}
+	}
+
+	if t.Pinned != nil {
+		tag.Pinned = *t.Pinned
+	}
+}
+
+// TagBulkRenameRequest represents a request to rename tags with a regular expression.
+type TagBulkRenameRequest struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// TagConversionRequest represents a request to snapshot the entries of a tag into a new cluster.
+// The cluster is named after the tag when ClusterName is empty.
+type TagConversionRequest struct {
+	ClusterName string     `json:"cluster_name"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// TagMergeRequest represents a request to merge tags into a target tag.
+type TagMergeRequest struct {
+	SourceTagIDs []int64 `json:"source_tag_ids"`
+}
+
+// TagCount represents a tag along with a number of occurrences.
+type TagCount struct {
+	TagID int64  `json:"tag_id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
 }
 
 // EntryTag represents the association between an entry and a tag.
 type EntryTag struct {
-	EntryID   int64     `json:"entry_id"`
-	TagID     int64     `json:"tag_id"`
-	Source    string    `json:"source"`
-	CreatedAt time.Time `json:"created_at"`
-	TagName   string    `json:"tag_name,omitempty"`
+	EntryID    int64     `json:"entry_id"`
+	TagID      int64     `json:"tag_id"`
+	Source     string    `json:"source"`
+	CreatedAt  time.Time `json:"created_at"`
+	TagName    string    `json:"tag_name,omitempty"`
+	EntryCount *int      `json:"entry_count,omitempty"`
 }
 
 // EntryTags represents a list of entry-tag associations.
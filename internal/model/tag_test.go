@@ -0,0 +1,40 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package model // import "miniflux.app/v2/internal/model"
+
+import "testing"
+
+func TestTagModificationRequestPatch(t *testing.T) {
+	description := "Old description"
+	tag := &Tag{Name: "golang", Description: &description}
+
+	name := "go"
+	pinned := true
+	request := &TagModificationRequest{Name: &name, Pinned: &pinned}
+	request.Patch(tag)
+
+	if tag.Name != "go" {
+		t.Errorf(`Unexpected tag name, got %q`, tag.Name)
+	}
+
+	if !tag.Pinned {
+		t.Error(`The tag should be pinned`)
+	}
+
+	if tag.Description == nil || *tag.Description != "Old description" {
+		t.Error(`The description should not change when it is not part of the request`)
+	}
+
+	emptyDescription := ""
+	request = &TagModificationRequest{Description: &emptyDescription}
+	request.Patch(tag)
+
+	if tag.Description != nil {
+		t.Error(`An empty description should clear the tag description`)
+	}
+
+	if !tag.Pinned {
+		t.Error(`The pinned flag should not change when it is not part of the request`)
+	}
+}
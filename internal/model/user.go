@@ -43,6 +43,7 @@ type User struct {
 	KeepFilterEntryRules            string     `json:"keep_filter_entry_rules"`
 	AlwaysOpenExternalLinks         bool       `json:"always_open_external_links"`
 	OpenExternalLinksInNewTab       bool       `json:"open_external_links_in_new_tab"`
+	StarredTagName                  string     `json:"starred_tag_name"`
 }
 
 // UserCreationRequest represents the request to create a user.
@@ -86,6 +87,7 @@ type UserModificationRequest struct {
 	KeepFilterEntryRules            *string  `json:"keep_filter_entry_rules"`
 	AlwaysOpenExternalLinks         *bool    `json:"always_open_external_links"`
 	OpenExternalLinksInNewTab       *bool    `json:"open_external_links_in_new_tab"`
+	StarredTagName                  *string  `json:"starred_tag_name"`
 }
 
 // Patch updates the User object with the modification request.
@@ -209,6 +211,10 @@ func (u *UserModificationRequest) Patch(user *User) {
 	if u.OpenExternalLinksInNewTab != nil {
 		user.OpenExternalLinksInNewTab = *u.OpenExternalLinksInNewTab
 	}
+
+	if u.StarredTagName != nil {
+		user.StarredTagName = *u.StarredTagName
+	}
 }
 
 // UseTimezone converts last login date to the given timezone.
@@ -5,25 +5,46 @@ package storage // import "miniflux.app/v2/internal/storage"
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/lib/pq"
+	"miniflux.app/v2/internal/config"
+	"miniflux.app/v2/internal/embedding"
 	"miniflux.app/v2/internal/model"
 )
 
-// ClusterByID returns a cluster by its ID.
+// ErrClusterNotFound is returned when a cluster doesn't exist or doesn't belong to the user.
+var ErrClusterNotFound = errors.New("store: cluster not found")
+
+// ClusterByID returns a cluster by its ID, along with its number of entries and feeds.
 func (s *Storage) ClusterByID(userID, clusterID int64) (*model.Cluster, error) {
 	var cluster model.Cluster
 	var expiresAt sql.NullTime
+	var entryCount, feedCount int
 
-	query := `SELECT id, user_id, name, created_at, expires_at FROM clusters WHERE user_id=$1 AND id=$2`
+	query := `
+		SELECT c.id, c.user_id, c.name, c.source, c.created_at, c.expires_at,
+		       (SELECT COUNT(*) FROM cluster_entries ce WHERE ce.cluster_id = c.id) as entry_count,
+		       (
+		           SELECT COUNT(DISTINCT e.feed_id)
+		           FROM cluster_entries ce
+		           JOIN entries e ON e.id = ce.entry_id
+		           WHERE ce.cluster_id = c.id
+		       ) as feed_count
+		FROM clusters c
+		WHERE c.user_id=$1 AND c.id=$2
+	`
 	err := s.db.QueryRow(query, userID, clusterID).Scan(
 		&cluster.ID,
 		&cluster.UserID,
 		&cluster.Name,
+		&cluster.Source,
 		&cluster.CreatedAt,
 		&expiresAt,
+		&entryCount,
+		&feedCount,
 	)
 
 	switch {
@@ -35,20 +56,97 @@ func (s *Storage) ClusterByID(userID, clusterID int64) (*model.Cluster, error) {
 		if expiresAt.Valid {
 			cluster.ExpiresAt = &expiresAt.Time
 		}
+		cluster.EntryCount = &entryCount
+		cluster.FeedCount = &feedCount
 		return &cluster, nil
 	}
 }
 
+// ClusterIDExists checks if a cluster exists for a user.
+func (s *Storage) ClusterIDExists(userID, clusterID int64) bool {
+	var result bool
+	query := `SELECT true FROM clusters WHERE user_id=$1 AND id=$2 LIMIT 1`
+	s.db.QueryRow(query, userID, clusterID).Scan(&result)
+	return result
+}
+
 // Clusters returns all non-expired clusters for a user.
-func (s *Storage) Clusters(userID int64) (model.Clusters, error) {
-	query := `
-		SELECT c.id, c.user_id, c.name, c.created_at, c.expires_at,
-		       (SELECT COUNT(*) FROM cluster_entries WHERE cluster_id = c.id) as entry_count
+// The freshness sort order lists first the clusters with the most recently published entries.
+// The unread sort order lists first the clusters with the most unread entries.
+// Otherwise, the most recently created clusters come first.
+// When multiFeedOnly is true, the clusters whose entries all come from the same feed are excluded.
+// When source is not empty, only the clusters created manually or automatically are returned.
+func (s *Storage) Clusters(userID int64, sortOrder string, multiFeedOnly bool, source string) (model.Clusters, error) {
+	orderBy := "c.created_at DESC"
+	switch sortOrder {
+	case model.ClusterSortFreshness:
+		orderBy = "MAX(e.published_at) DESC NULLS LAST, c.created_at DESC"
+	case model.ClusterSortUnread:
+		orderBy = "unread_count DESC, c.created_at DESC"
+	}
+
+	having := ""
+	if multiFeedOnly {
+		having = "HAVING COUNT(DISTINCT e.feed_id) > 1"
+	}
+
+	if source != "" {
+		return s.queryClusters(userID, "AND c.source = $2", having, orderBy, source)
+	}
+
+	return s.queryClusters(userID, "", having, orderBy)
+}
+
+// CountClusters returns the number of clusters of a user, including the expired ones only when includeExpired is true.
+func (s *Storage) CountClusters(userID int64, includeExpired bool) (int, error) {
+	query := `SELECT COUNT(*) FROM clusters c WHERE c.user_id = $1 AND ($2 OR c.expires_at IS NULL OR c.expires_at > NOW())`
+
+	var count int
+	if err := s.db.QueryRow(query, userID, includeExpired).Scan(&count); err != nil {
+		return 0, fmt.Errorf(`store: unable to count clusters: %v`, err)
+	}
+
+	return count, nil
+}
+
+// UnviewedClusters returns the non-expired clusters created since the user last viewed their clusters, most recent first.
+// All the clusters are returned when the user never viewed them.
+func (s *Storage) UnviewedClusters(userID int64) (model.Clusters, error) {
+	condition := `AND c.created_at > COALESCE((SELECT clusters_viewed_at FROM users WHERE id = $1), '-infinity')`
+	return s.queryClusters(userID, condition, "", "c.created_at DESC")
+}
+
+// ClustersExpiringSoon returns the clusters of a user that expire within the given duration, the first to expire first.
+func (s *Storage) ClustersExpiringSoon(userID int64, within time.Duration) (model.Clusters, error) {
+	condition := `AND c.expires_at IS NOT NULL AND c.expires_at <= NOW() + INTERVAL '1 second' * $2`
+	return s.queryClusters(userID, condition, "", "c.expires_at ASC", int64(within.Seconds()))
+}
+
+// MarkClustersViewed records that the user viewed their clusters now.
+func (s *Storage) MarkClustersViewed(userID int64) error {
+	if _, err := s.db.Exec(`UPDATE users SET clusters_viewed_at = NOW() WHERE id = $1`, userID); err != nil {
+		return fmt.Errorf(`store: unable to mark clusters as viewed: %v`, err)
+	}
+
+	return nil
+}
+
+// queryClusters returns the non-expired clusters of a user matching the condition, along with their entry counts.
+func (s *Storage) queryClusters(userID int64, condition, having, orderBy string, args ...any) (model.Clusters, error) {
+	query := fmt.Sprintf(`
+		SELECT c.id, c.user_id, c.name, c.source, c.created_at, c.expires_at,
+		       COUNT(e.id) as entry_count,
+		       COUNT(e.id) FILTER (WHERE e.status = 'unread') as unread_count,
+		       COUNT(DISTINCT e.feed_id) as feed_count
 		FROM clusters c
-		WHERE c.user_id = $1 AND (c.expires_at IS NULL OR c.expires_at > NOW())
-		ORDER BY c.created_at DESC
-	`
-	rows, err := s.db.Query(query, userID)
+		LEFT JOIN cluster_entries ce ON ce.cluster_id = c.id
+		LEFT JOIN entries e ON e.id = ce.entry_id
+		WHERE c.user_id = $1 AND (c.expires_at IS NULL OR c.expires_at > NOW()) %s
+		GROUP BY c.id
+		%s
+		ORDER BY %s
+	`, condition, having, orderBy)
+	rows, err := s.db.Query(query, append([]any{userID}, args...)...)
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to fetch clusters: %v`, err)
 	}
@@ -58,9 +156,9 @@ func (s *Storage) Clusters(userID int64) (model.Clusters, error) {
 	for rows.Next() {
 		var cluster model.Cluster
 		var expiresAt sql.NullTime
-		var entryCount int
+		var entryCount, unreadCount, feedCount int
 
-		if err := rows.Scan(&cluster.ID, &cluster.UserID, &cluster.Name, &cluster.CreatedAt, &expiresAt, &entryCount); err != nil {
+		if err := rows.Scan(&cluster.ID, &cluster.UserID, &cluster.Name, &cluster.Source, &cluster.CreatedAt, &expiresAt, &entryCount, &unreadCount, &feedCount); err != nil {
 			return nil, fmt.Errorf(`store: unable to fetch cluster row: %v`, err)
 		}
 
@@ -68,32 +166,41 @@ func (s *Storage) Clusters(userID int64) (model.Clusters, error) {
 			cluster.ExpiresAt = &expiresAt.Time
 		}
 		cluster.EntryCount = &entryCount
+		cluster.UnreadCount = &unreadCount
+		cluster.FeedCount = &feedCount
 		clusters = append(clusters, &cluster)
 	}
 
 	return clusters, nil
 }
 
-// CreateCluster creates a new cluster.
-func (s *Storage) CreateCluster(userID int64, name string, expiresAt *time.Time) (*model.Cluster, error) {
+// CreateCluster creates a new cluster with the given source (manual or auto).
+// Without a name, the cluster is named after the title of the seed entry.
+func (s *Storage) CreateCluster(userID int64, name, source string, expiresAt *time.Time, seedEntryID int64) (*model.Cluster, error) {
 	var cluster model.Cluster
 	var nullExpiresAt sql.NullTime
 
+	name, err := clusterNameOrSeedTitle(s.db, userID, name, seedEntryID)
+	if err != nil {
+		return nil, err
+	}
+
 	if expiresAt != nil {
 		nullExpiresAt.Time = *expiresAt
 		nullExpiresAt.Valid = true
 	}
 
 	query := `
-		INSERT INTO clusters (user_id, name, expires_at)
-		VALUES ($1, $2, $3)
-		RETURNING id, user_id, name, created_at, expires_at
+		INSERT INTO clusters (user_id, name, source, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, name, source, created_at, expires_at
 	`
 	var retExpiresAt sql.NullTime
-	err := s.db.QueryRow(query, userID, name, nullExpiresAt).Scan(
+	err = s.db.QueryRow(query, userID, name, source, nullExpiresAt).Scan(
 		&cluster.ID,
 		&cluster.UserID,
 		&cluster.Name,
+		&cluster.Source,
 		&cluster.CreatedAt,
 		&retExpiresAt,
 	)
@@ -106,9 +213,158 @@ func (s *Storage) CreateCluster(userID int64, name string, expiresAt *time.Time)
 		cluster.ExpiresAt = &retExpiresAt.Time
 	}
 
+	entryCount := 0
+	cluster.EntryCount = &entryCount
+
 	return &cluster, nil
 }
 
+// CreateClustersBatch creates several clusters and their entry associations in a single transaction.
+// Entry IDs that do not belong to the user are ignored. The clusters without a name are named after their first entry.
+func (s *Storage) CreateClustersBatch(userID int64, groups []model.ClusterSpec) (model.Clusters, error) {
+	clusters := make(model.Clusters, 0, len(groups))
+	if len(groups) == 0 {
+		return clusters, nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	clusterEntryIDs := make(map[int64][]int64, len(groups))
+	for _, group := range groups {
+		var cluster model.Cluster
+		var nullExpiresAt sql.NullTime
+
+		if group.ExpiresAt != nil {
+			nullExpiresAt.Time = *group.ExpiresAt
+			nullExpiresAt.Valid = true
+		}
+
+		source := group.Source
+		if source == "" {
+			source = model.ClusterSourceManual
+		}
+
+		var seedEntryID int64
+		if len(group.EntryIDs) > 0 {
+			seedEntryID = group.EntryIDs[0]
+		}
+
+		name, err := clusterNameOrSeedTitle(tx, userID, group.Name, seedEntryID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		query := `
+			INSERT INTO clusters (user_id, name, source, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, user_id, name, source, created_at, expires_at
+		`
+		var retExpiresAt sql.NullTime
+		err = tx.QueryRow(query, userID, name, source, nullExpiresAt).Scan(
+			&cluster.ID,
+			&cluster.UserID,
+			&cluster.Name,
+			&cluster.Source,
+			&cluster.CreatedAt,
+			&retExpiresAt,
+		)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf(`store: unable to create cluster: %v`, err)
+		}
+
+		if retExpiresAt.Valid {
+			cluster.ExpiresAt = &retExpiresAt.Time
+		}
+
+		query = `
+			INSERT INTO cluster_entries (cluster_id, entry_id)
+			SELECT $1, e.id FROM entries e WHERE e.user_id = $2 AND e.id = ANY($3)
+			ON CONFLICT DO NOTHING
+			RETURNING entry_id
+		`
+		if group.CollapseDuplicates {
+			query = `
+				INSERT INTO cluster_entries (cluster_id, entry_id)
+				SELECT DISTINCT ON (e.hash) $1, e.id FROM entries e WHERE e.user_id = $2 AND e.id = ANY($3)
+				ORDER BY e.hash, array_position($3, e.id)
+				ON CONFLICT DO NOTHING
+				RETURNING entry_id
+			`
+		}
+		entryIDs, err := queryEntryIDs(tx, query, cluster.ID, userID, pq.Array(group.EntryIDs))
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf(`store: unable to add entries to cluster: %v`, err)
+		}
+
+		entryCount := len(entryIDs)
+		cluster.EntryCount = &entryCount
+		clusterEntryIDs[cluster.ID] = entryIDs
+
+		clusters = append(clusters, &cluster)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	for _, cluster := range clusters {
+		if err := s.RecomputeClusterCentroid(cluster.ID); err != nil {
+			return nil, err
+		}
+
+		if err := s.applyClusterMembershipTags(cluster.ID, clusterEntryIDs[cluster.ID]); err != nil {
+			return nil, err
+		}
+	}
+
+	return clusters, nil
+}
+
+// clusterNameOrSeedTitle returns the name, or the title of the seed entry when the name is empty.
+func clusterNameOrSeedTitle(db interface {
+	QueryRow(query string, args ...any) *sql.Row
+}, userID int64, name string, seedEntryID int64) (string, error) {
+	if name != "" || seedEntryID == 0 {
+		return name, nil
+	}
+
+	var seedEntry model.Entry
+	err := db.QueryRow(`SELECT title FROM entries WHERE id=$1 AND user_id=$2`, seedEntryID, userID).Scan(&seedEntry.Title)
+	if err == sql.ErrNoRows {
+		return name, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf(`store: unable to fetch seed entry #%d: %v`, seedEntryID, err)
+	}
+
+	return model.ClusterNameFromSeed(&seedEntry), nil
+}
+
+func queryEntryIDs(tx *sql.Tx, query string, args ...any) ([]int64, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entryIDs []int64
+	for rows.Next() {
+		var entryID int64
+		if err := rows.Scan(&entryID); err != nil {
+			return nil, err
+		}
+		entryIDs = append(entryIDs, entryID)
+	}
+
+	return entryIDs, rows.Err()
+}
+
 // AddEntryToCluster adds an entry to a cluster.
 func (s *Storage) AddEntryToCluster(clusterID, entryID int64) error {
 	query := `
@@ -121,11 +377,26 @@ func (s *Storage) AddEntryToCluster(clusterID, entryID int64) error {
 		return fmt.Errorf(`store: unable to add entry to cluster: %v`, err)
 	}
 
-	return nil
+	if err := s.RecomputeClusterCentroid(clusterID); err != nil {
+		return err
+	}
+
+	return s.applyClusterMembershipTags(clusterID, []int64{entryID})
 }
 
-// AddEntriesToCluster adds multiple entries to a cluster.
-func (s *Storage) AddEntriesToCluster(clusterID int64, entryIDs []int64) error {
+// AddEntriesToCluster adds multiple entries to a cluster, ignoring duplicate IDs.
+// When collapseDuplicates is set, entries sharing the same content hash as another given entry
+// or as an entry already in the cluster are skipped, so the cluster holds distinct stories only.
+func (s *Storage) AddEntriesToCluster(clusterID int64, entryIDs []int64, collapseDuplicates bool) error {
+	entryIDs = uniqueEntryIDs(entryIDs)
+
+	if collapseDuplicates && len(entryIDs) > 0 {
+		var err error
+		if entryIDs, err = s.entryIDsWithDistinctHash(clusterID, entryIDs); err != nil {
+			return err
+		}
+	}
+
 	if len(entryIDs) == 0 {
 		return nil
 	}
@@ -153,86 +424,648 @@ func (s *Storage) AddEntriesToCluster(clusterID int64, entryIDs []int64) error {
 		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
 	}
 
-	return nil
+	if err := s.RecomputeClusterCentroid(clusterID); err != nil {
+		return err
+	}
+
+	return s.applyClusterMembershipTags(clusterID, entryIDs)
+}
+
+// entryIDsWithDistinctHash keeps the first entry of each content hash, in the given order,
+// and drops the entries whose hash is already represented in the cluster.
+func (s *Storage) entryIDsWithDistinctHash(clusterID int64, entryIDs []int64) ([]int64, error) {
+	query := `
+		SELECT id FROM (
+			SELECT DISTINCT ON (e.hash) e.id, array_position($1, e.id) as position
+			FROM entries e
+			WHERE e.id = ANY($1)
+			  AND NOT EXISTS (
+				SELECT 1
+				FROM cluster_entries ce
+				JOIN entries member ON member.id = ce.entry_id
+				WHERE ce.cluster_id = $2 AND member.hash = e.hash AND member.id <> e.id
+			  )
+			ORDER BY e.hash, position
+		) distinct_entries
+		ORDER BY position
+	`
+	rows, err := s.db.Query(query, pq.Array(entryIDs), clusterID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to collapse duplicate cluster entries: %v`, err)
+	}
+	defer rows.Close()
+
+	distinctIDs := make([]int64, 0, len(entryIDs))
+	for rows.Next() {
+		var entryID int64
+		if err := rows.Scan(&entryID); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch distinct cluster entry row: %v`, err)
+		}
+		distinctIDs = append(distinctIDs, entryID)
+	}
+
+	return distinctIDs, nil
+}
+
+func uniqueEntryIDs(entryIDs []int64) []int64 {
+	seen := make(map[int64]bool, len(entryIDs))
+	result := make([]int64, 0, len(entryIDs))
+	for _, entryID := range entryIDs {
+		if !seen[entryID] {
+			seen[entryID] = true
+			result = append(result, entryID)
+		}
+	}
+	return result
 }
 
 // RemoveEntryFromCluster removes an entry from a cluster.
 func (s *Storage) RemoveEntryFromCluster(clusterID, entryID int64) error {
+	if err := s.removeClusterMembershipTags(clusterID, []int64{entryID}); err != nil {
+		return err
+	}
+
 	query := `DELETE FROM cluster_entries WHERE cluster_id = $1 AND entry_id = $2`
 	_, err := s.db.Exec(query, clusterID, entryID)
 	if err != nil {
 		return fmt.Errorf(`store: unable to remove entry from cluster: %v`, err)
 	}
 
+	return s.RecomputeClusterCentroid(clusterID)
+}
+
+// applyClusterMembershipTags tags the entries with the name of the cluster when membership tags are enabled.
+// Entries already carrying a tag with that name keep their existing association and source.
+func (s *Storage) applyClusterMembershipTags(clusterID int64, entryIDs []int64) error {
+	if !config.Opts.ClusteringMembershipTags() || len(entryIDs) == 0 {
+		return nil
+	}
+
+	var userID int64
+	var name string
+	err := s.db.QueryRow(`SELECT user_id, name FROM clusters WHERE id = $1`, clusterID).Scan(&userID, &name)
+	if err != nil {
+		return fmt.Errorf(`store: unable to fetch cluster #%d: %v`, clusterID, err)
+	}
+
+	taggedEntryIDs, err := s.entriesWithTagName(userID, entryIDs, name)
+	if err != nil {
+		return err
+	}
+
+	for _, entryID := range entryIDs {
+		if taggedEntryIDs[entryID] {
+			continue
+		}
+
+		err := s.AddTagToEntryByName(userID, entryID, name, model.TagSourceAuto)
+		if errors.Is(err, ErrEntryTagLimitReached) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// GetClusterEntries returns all entries in a cluster.
-func (s *Storage) GetClusterEntries(userID, clusterID int64) (model.Entries, error) {
+func (s *Storage) entriesWithTagName(userID int64, entryIDs []int64, name string) (map[int64]bool, error) {
 	query := `
-		SELECT
-			e.id, e.user_id, e.feed_id, e.hash, e.published_at, e.title, e.url,
-			e.comments_url, e.author, e.content, e.status, e.starred, e.reading_time,
-			e.created_at, e.changed_at, e.share_code,
-			f.title as feed_title, f.site_url as feed_site_url,
-			f.icon_id, c.id as category_id, c.title as category_title
+		SELECT et.entry_id
+		FROM entry_tags et
+		JOIN tags t ON t.id = et.tag_id
+		WHERE t.user_id = $1 AND lower(t.name) = lower($2) AND et.entry_id = ANY($3)
+	`
+	rows, err := s.db.Query(query, userID, name, pq.Array(entryIDs))
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch tagged entries: %v`, err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64]bool)
+	for rows.Next() {
+		var entryID int64
+		if err := rows.Scan(&entryID); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch tagged entry row: %v`, err)
+		}
+		result[entryID] = true
+	}
+
+	return result, nil
+}
+
+// removeClusterMembershipTags removes the auto tags named after the cluster from its entries,
+// or from the given entries only. Tags still justified by another cluster with the same name are kept.
+func (s *Storage) removeClusterMembershipTags(clusterID int64, entryIDs []int64) error {
+	if !config.Opts.ClusteringMembershipTags() {
+		return nil
+	}
+
+	_, err := s.db.Exec(removeClusterMembershipTagsQuery, clusterID, model.TagSourceAuto, pq.Array(entryIDs))
+	if err != nil {
+		return fmt.Errorf(`store: unable to remove cluster membership tags: %v`, err)
+	}
+
+	return nil
+}
+
+// removeClusterMembershipTagsQuery deletes the auto tags (source $2) named after the cluster $1 from its entries,
+// or from the entries $3 only when not NULL.
+const removeClusterMembershipTagsQuery = `
+		DELETE FROM entry_tags et
+		USING clusters c, tags t
+		WHERE c.id = $1
+		  AND t.user_id = c.user_id
+		  AND lower(t.name) = lower(c.name)
+		  AND et.tag_id = t.id
+		  AND et.source = $2
+		  AND et.entry_id IN (SELECT entry_id FROM cluster_entries WHERE cluster_id = $1)
+		  AND ($3::bigint[] IS NULL OR et.entry_id = ANY($3))
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM cluster_entries ce
+			JOIN clusters other ON other.id = ce.cluster_id
+			WHERE ce.entry_id = et.entry_id
+			  AND other.id <> c.id
+			  AND other.user_id = c.user_id
+			  AND lower(other.name) = lower(c.name)
+		  )
+	`
+
+// clusterEntryColumns lists the columns read by scanClusterEntry.
+// Queries using it must join the feeds, categories (as cat), feed_icons and icons tables.
+const clusterEntryColumns = `
+	e.id, e.user_id, e.feed_id, e.hash, e.published_at, e.title, e.url,
+	e.comments_url, e.author, e.content, e.status, e.starred, e.reading_time,
+	e.created_at, e.changed_at, e.share_code,
+	f.title as feed_title, f.site_url as feed_site_url,
+	cat.id as category_id, cat.title as category_title,
+	fi.icon_id, i.external_id as icon_external_id
+`
+
+// scanClusterEntry reads an entry selected with clusterEntryColumns.
+// The leading destinations are filled with the columns selected before clusterEntryColumns.
+func scanClusterEntry(rows *sql.Rows, leading ...any) (*model.Entry, error) {
+	var entry model.Entry
+	var iconID sql.NullInt64
+	var externalIconID sql.NullString
+
+	entry.Feed = &model.Feed{}
+	entry.Feed.Category = &model.Category{}
+	entry.Feed.Icon = &model.FeedIcon{}
+
+	dest := append(leading,
+		&entry.ID,
+		&entry.UserID,
+		&entry.FeedID,
+		&entry.Hash,
+		&entry.Date,
+		&entry.Title,
+		&entry.URL,
+		&entry.CommentsURL,
+		&entry.Author,
+		&entry.Content,
+		&entry.Status,
+		&entry.Starred,
+		&entry.ReadingTime,
+		&entry.CreatedAt,
+		&entry.ChangedAt,
+		&entry.ShareCode,
+		&entry.Feed.Title,
+		&entry.Feed.SiteURL,
+		&entry.Feed.Category.ID,
+		&entry.Feed.Category.Title,
+		&iconID,
+		&externalIconID,
+	)
+
+	if err := rows.Scan(dest...); err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch cluster entry row: %v`, err)
+	}
+
+	entry.Feed.ID = entry.FeedID
+	entry.Feed.Category.UserID = entry.UserID
+	if iconID.Valid && externalIconID.Valid && externalIconID.String != "" {
+		entry.Feed.Icon.FeedID = entry.FeedID
+		entry.Feed.Icon.IconID = iconID.Int64
+		entry.Feed.Icon.ExternalIconID = externalIconID.String
+	}
+
+	return &entry, nil
+}
+
+// ClusterEntriesOptions filters and paginates the entries returned by GetClusterEntries.
+type ClusterEntriesOptions struct {
+	StarredOnly bool
+	Limit       int
+	Offset      int
+}
+
+// GetClusterEntries returns the entries in a cluster, optionally filtered and paginated.
+func (s *Storage) GetClusterEntries(userID, clusterID int64, opts *ClusterEntriesOptions) (model.Entries, error) {
+	entries := make(model.Entries, 0)
+	err := s.iterateClusterEntries(userID, clusterID, opts, func(entry *model.Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// IterateClusterEntries calls fn for each entry of a cluster, newest first, without loading all entries in memory.
+// The iteration stops at the first error returned by fn, and this error is returned as is.
+func (s *Storage) IterateClusterEntries(userID, clusterID int64, fn func(*model.Entry) error) error {
+	return s.iterateClusterEntries(userID, clusterID, nil, fn)
+}
+
+func (s *Storage) iterateClusterEntries(userID, clusterID int64, opts *ClusterEntriesOptions, fn func(*model.Entry) error) error {
+	if opts == nil {
+		opts = &ClusterEntriesOptions{}
+	}
+
+	if !s.ClusterIDExists(userID, clusterID) {
+		return ErrClusterNotFound
+	}
+
+	query := `
+		SELECT ` + clusterEntryColumns + `
 		FROM entries e
 		JOIN cluster_entries ce ON e.id = ce.entry_id
 		JOIN feeds f ON e.feed_id = f.id
-		JOIN categories c ON f.category_id = c.id
+		JOIN categories cat ON f.category_id = cat.id
+		LEFT JOIN feed_icons fi ON fi.feed_id = f.id
+		LEFT JOIN icons i ON i.id = fi.icon_id
+		WHERE ce.cluster_id = $1 AND e.user_id = $2
+	`
+	args := []any{clusterID, userID}
+
+	if opts.StarredOnly {
+		query += ` AND e.starred = true`
+	}
+
+	query += ` ORDER BY e.published_at DESC`
+
+	if opts.Limit > 0 {
+		args = append(args, opts.Limit)
+		query += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+
+	if opts.Offset > 0 {
+		args = append(args, opts.Offset)
+		query += fmt.Sprintf(` OFFSET $%d`, len(args))
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf(`store: unable to fetch cluster entries: %v`, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry, err := scanClusterEntry(rows)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf(`store: unable to iterate cluster entries: %v`, err)
+	}
+
+	return nil
+}
+
+// AddTagToClusterEntries applies a tag to all the entries of a cluster in a single transaction.
+// The tag is created when the user doesn't have a tag with this name yet.
+// Entries that reached their tag limit, or auto tags on feeds with auto-tagging disabled, are skipped.
+func (s *Storage) AddTagToClusterEntries(userID, clusterID int64, tagName, source string) error {
+	if source == "" {
+		source = model.TagSourceManual
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(`SELECT true FROM clusters WHERE id=$1 AND user_id=$2`, clusterID, userID).Scan(&exists)
+	switch {
+	case err == sql.ErrNoRows:
+		tx.Rollback()
+		return ErrClusterNotFound
+	case err != nil:
+		tx.Rollback()
+		return fmt.Errorf(`store: unable to fetch cluster #%d: %v`, clusterID, err)
+	}
+
+	tagID, err := tagClusterEntries(tx, userID, clusterID, tagName, source)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	return s.refreshTagExpiry(tagID, source)
+}
+
+// ConvertClusterToTag applies a tag named tagName to all the entries of a cluster with the manual source
+// and returns the tag. The tag is created when the user doesn't have a global tag with this name yet.
+// When deleteCluster is true, the cluster is removed in the same transaction.
+func (s *Storage) ConvertClusterToTag(userID, clusterID int64, tagName string, deleteCluster bool) (*model.Tag, error) {
+	if !s.ClusterIDExists(userID, clusterID) {
+		return nil, ErrClusterNotFound
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	if deleteCluster && config.Opts.ClusteringMembershipTags() {
+		if _, err := tx.Exec(removeClusterMembershipTagsQuery, clusterID, model.TagSourceAuto, pq.Array([]int64(nil))); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf(`store: unable to remove cluster membership tags: %v`, err)
+		}
+	}
+
+	tagID, err := tagClusterEntries(tx, userID, clusterID, tagName, model.TagSourceManual)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if deleteCluster {
+		if _, err := tx.Exec(`DELETE FROM clusters WHERE id = $1 AND user_id = $2`, clusterID, userID); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf(`store: unable to remove cluster: %v`, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	if err := s.refreshTagExpiry(tagID, model.TagSourceManual); err != nil {
+		return nil, err
+	}
+
+	return s.TagByID(userID, tagID)
+}
+
+// ConvertTagToCluster creates a manual cluster made of the entries currently carrying the tag.
+// The cluster is named after the tag when clusterName is empty.
+// It returns ErrTagNotFound when the tag doesn't belong to the user.
+func (s *Storage) ConvertTagToCluster(userID, tagID int64, clusterName string, expiresAt *time.Time) (*model.Cluster, error) {
+	tag, err := s.TagByID(userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil {
+		return nil, ErrTagNotFound
+	}
+
+	if clusterName == "" {
+		clusterName = tag.Name
+	}
+
+	entryIDs, err := s.GetEntriesWithTag(userID, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cluster and its entries are inserted in a single transaction, then the centroid is computed.
+	clusters, err := s.CreateClustersBatch(userID, []model.ClusterSpec{{
+		Name:      clusterName,
+		Source:    model.ClusterSourceManual,
+		ExpiresAt: expiresAt,
+		EntryIDs:  entryIDs,
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ClusterByID(userID, clusters[0].ID)
+}
+
+// tagClusterEntries applies a tag to all the entries of a cluster and returns the tag ID.
+// The tag is created when the user doesn't have a global tag with this name yet.
+func tagClusterEntries(tx *sql.Tx, userID, clusterID int64, tagName, source string) (int64, error) {
+	var tagID int64
+	err := tx.QueryRow(`SELECT id FROM tags WHERE user_id=$1 AND lower(name)=lower($2) AND category_id IS NULL`, userID, tagName).Scan(&tagID)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(`INSERT INTO tags (user_id, name) VALUES ($1, $2) RETURNING id`, userID, tagName).Scan(&tagID)
+		if err == nil {
+			_, err = tx.Exec(bumpTagVersionQuery, userID)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to resolve tag %q: %v`, tagName, err)
+	}
+
+	query := `
+		INSERT INTO entry_tags (entry_id, tag_id, source)
+		SELECT e.id, $3, $4::tag_source
+		FROM cluster_entries ce
+		JOIN entries e ON e.id = ce.entry_id
+		JOIN feeds f ON f.id = e.feed_id
+		WHERE ce.cluster_id = $1
+		  AND e.user_id = $2
+		  AND NOT ($4::tag_source = $5::tag_source AND f.disable_auto_tagging)
+		  AND (
+			EXISTS (SELECT 1 FROM entry_tags et WHERE et.entry_id = e.id AND et.tag_id = $3)
+			OR (
+				(SELECT COUNT(*) FROM entry_tags et WHERE et.entry_id = e.id) < $6
+				AND ($4 <> $5 OR (SELECT COUNT(*) FROM entry_tags et WHERE et.entry_id = e.id AND et.source = $5) < $7)
+			)
+		  )
+		ON CONFLICT (entry_id, tag_id) DO UPDATE SET source = EXCLUDED.source
+	`
+	_, err = tx.Exec(query,
+		clusterID,
+		userID,
+		tagID,
+		source,
+		model.TagSourceAuto,
+		config.Opts.TagsMaxPerEntry(),
+		config.Opts.TagsMaxAutoPerEntry(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to tag entries of cluster #%d: %v`, clusterID, err)
+	}
+
+	return tagID, nil
+}
+
+// RemoveTagFromClusterEntries removes a tag from all the entries of a cluster.
+// Entries outside the cluster keep the tag.
+func (s *Storage) RemoveTagFromClusterEntries(userID, clusterID, tagID int64) error {
+	if !s.ClusterIDExists(userID, clusterID) {
+		return ErrClusterNotFound
+	}
+
+	query := `
+		DELETE FROM entry_tags et
+		USING cluster_entries ce, tags t
+		WHERE ce.cluster_id = $1
+		  AND et.entry_id = ce.entry_id
+		  AND et.tag_id = $2
+		  AND t.id = et.tag_id
+		  AND t.user_id = $3
+	`
+	if _, err := s.db.Exec(query, clusterID, tagID, userID); err != nil {
+		return fmt.Errorf(`store: unable to remove tag #%d from entries of cluster #%d: %v`, tagID, clusterID, err)
+	}
+
+	return nil
+}
+
+// RecentEntriesPerCluster returns, for each given cluster of the user, its perCluster most recent entries.
+// Clusters that don't belong to the user or don't have any entry are absent from the map.
+func (s *Storage) RecentEntriesPerCluster(userID int64, clusterIDs []int64, perCluster int) (map[int64]model.Entries, error) {
+	entriesByCluster := make(map[int64]model.Entries)
+	if len(clusterIDs) == 0 || perCluster <= 0 {
+		return entriesByCluster, nil
+	}
+
+	query := `
+		SELECT ranked.cluster_id, ` + clusterEntryColumns + `
+		FROM (
+			SELECT
+				ce.cluster_id,
+				ce.entry_id,
+				ROW_NUMBER() OVER (PARTITION BY ce.cluster_id ORDER BY e.published_at DESC, e.id DESC) as position
+			FROM cluster_entries ce
+			JOIN clusters c ON c.id = ce.cluster_id
+			JOIN entries e ON e.id = ce.entry_id
+			WHERE c.user_id = $1 AND ce.cluster_id = ANY($2)
+		) ranked
+		JOIN entries e ON e.id = ranked.entry_id
+		JOIN feeds f ON e.feed_id = f.id
+		JOIN categories cat ON f.category_id = cat.id
+		LEFT JOIN feed_icons fi ON fi.feed_id = f.id
+		LEFT JOIN icons i ON i.id = fi.icon_id
+		WHERE ranked.position <= $3
+		ORDER BY ranked.cluster_id, ranked.position
+	`
+
+	rows, err := s.db.Query(query, userID, pq.Array(clusterIDs), perCluster)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch recent cluster entries: %v`, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var clusterID int64
+		entry, err := scanClusterEntry(rows, &clusterID)
+		if err != nil {
+			return nil, err
+		}
+
+		entriesByCluster[clusterID] = append(entriesByCluster[clusterID], entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf(`store: unable to iterate recent cluster entries: %v`, err)
+	}
+
+	return entriesByCluster, nil
+}
+
+// ClusterEntryTimeline returns the number of entries of a cluster published each day, oldest first.
+// Days without any entry are omitted.
+func (s *Storage) ClusterEntryTimeline(userID, clusterID int64) ([]model.TimelinePoint, error) {
+	if !s.ClusterIDExists(userID, clusterID) {
+		return nil, ErrClusterNotFound
+	}
+
+	query := `
+		SELECT date_trunc('day', e.published_at) as day, COUNT(*)
+		FROM cluster_entries ce
+		JOIN entries e ON e.id = ce.entry_id
+		WHERE ce.cluster_id = $1 AND e.user_id = $2
+		GROUP BY day
+		ORDER BY day ASC
+	`
+	rows, err := s.db.Query(query, clusterID, userID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch cluster timeline: %v`, err)
+	}
+	defer rows.Close()
+
+	timeline := make([]model.TimelinePoint, 0)
+	for rows.Next() {
+		var point model.TimelinePoint
+		if err := rows.Scan(&point.Date, &point.Count); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch cluster timeline row: %v`, err)
+		}
+		timeline = append(timeline, point)
+	}
+
+	return timeline, nil
+}
+
+// ClusterDateRange returns the publication dates of the oldest and most recent entries of a cluster.
+// The dates are zero and found is false when the cluster has no entry.
+func (s *Storage) ClusterDateRange(userID, clusterID int64) (earliest, latest time.Time, found bool, err error) {
+	if !s.ClusterIDExists(userID, clusterID) {
+		return earliest, latest, false, ErrClusterNotFound
+	}
+
+	var minPublishedAt, maxPublishedAt sql.NullTime
+	query := `
+		SELECT MIN(e.published_at), MAX(e.published_at)
+		FROM cluster_entries ce
+		JOIN entries e ON e.id = ce.entry_id
 		WHERE ce.cluster_id = $1 AND e.user_id = $2
-		ORDER BY e.published_at DESC
 	`
-	rows, err := s.db.Query(query, clusterID, userID)
-	if err != nil {
-		return nil, fmt.Errorf(`store: unable to fetch cluster entries: %v`, err)
+	if err := s.db.QueryRow(query, clusterID, userID).Scan(&minPublishedAt, &maxPublishedAt); err != nil {
+		return earliest, latest, false, fmt.Errorf(`store: unable to fetch cluster date range: %v`, err)
 	}
-	defer rows.Close()
-
-	entries := make(model.Entries, 0)
-	for rows.Next() {
-		var entry model.Entry
-		var iconID sql.NullInt64
 
-		entry.Feed = &model.Feed{}
-		entry.Feed.Category = &model.Category{}
-		entry.Feed.Icon = &model.FeedIcon{}
+	if !minPublishedAt.Valid || !maxPublishedAt.Valid {
+		return earliest, latest, false, nil
+	}
 
-		err := rows.Scan(
-			&entry.ID,
-			&entry.UserID,
-			&entry.FeedID,
-			&entry.Hash,
-			&entry.Date,
-			&entry.Title,
-			&entry.URL,
-			&entry.CommentsURL,
-			&entry.Author,
-			&entry.Content,
-			&entry.Status,
-			&entry.Starred,
-			&entry.ReadingTime,
-			&entry.CreatedAt,
-			&entry.ChangedAt,
-			&entry.ShareCode,
-			&entry.Feed.Title,
-			&entry.Feed.SiteURL,
-			&iconID,
-			&entry.Feed.Category.ID,
-			&entry.Feed.Category.Title,
-		)
-		if err != nil {
-			return nil, fmt.Errorf(`store: unable to fetch cluster entry row: %v`, err)
-		}
+	return minPublishedAt.Time, maxPublishedAt.Time, true, nil
+}
 
-		if iconID.Valid {
-			entry.Feed.Icon.IconID = iconID.Int64
-		}
+// MarkClusterEntriesAsRead updates all unread entries of a cluster to the read status.
+func (s *Storage) MarkClusterEntriesAsRead(userID, clusterID int64) (int64, error) {
+	query := `
+		UPDATE
+			entries e
+		SET
+			status=$1,
+			changed_at=now()
+		FROM
+			cluster_entries ce, clusters c
+		WHERE
+			ce.entry_id = e.id AND c.id = ce.cluster_id AND
+			c.id=$2 AND c.user_id=$3 AND e.user_id=$3 AND e.status=$4
+	`
+	result, err := s.db.Exec(query, model.EntryStatusRead, clusterID, userID, model.EntryStatusUnread)
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to mark cluster entries as read: %v`, err)
+	}
 
-		entries = append(entries, &entry)
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to mark cluster entries as read: %v`, err)
 	}
 
-	return entries, nil
+	return count, nil
 }
 
 // GetClusterWithEntries returns a cluster with all its entries.
@@ -246,7 +1079,7 @@ func (s *Storage) GetClusterWithEntries(userID, clusterID int64) (*model.Cluster
 		return nil, nil
 	}
 
-	entries, err := s.GetClusterEntries(userID, clusterID)
+	entries, err := s.GetClusterEntries(userID, clusterID, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -255,61 +1088,216 @@ func (s *Storage) GetClusterWithEntries(userID, clusterID int64) (*model.Cluster
 	count := len(entries)
 	cluster.EntryCount = &count
 
+	unreadCount := 0
+	feedIDs := make(map[int64]bool)
+	for _, entry := range entries {
+		if entry.Status == model.EntryStatusUnread {
+			unreadCount++
+		}
+		feedIDs[entry.FeedID] = true
+	}
+	cluster.UnreadCount = &unreadCount
+	feedCount := len(feedIDs)
+	cluster.FeedCount = &feedCount
+
 	return cluster, nil
 }
 
-// RemoveCluster removes a cluster and all its entry associations.
-func (s *Storage) RemoveCluster(userID, clusterID int64) error {
-	query := `DELETE FROM clusters WHERE id = $1 AND user_id = $2`
-	result, err := s.db.Exec(query, clusterID, userID)
+// ExtendClusterExpiry changes the expiration date of a cluster. A nil date makes the cluster permanent.
+func (s *Storage) ExtendClusterExpiry(userID, clusterID int64, newExpiresAt *time.Time) error {
+	var expiresAt sql.NullTime
+	if newExpiresAt != nil {
+		expiresAt.Time = *newExpiresAt
+		expiresAt.Valid = true
+	}
+
+	query := `UPDATE clusters SET expires_at = $1 WHERE id = $2 AND user_id = $3`
+	result, err := s.db.Exec(query, expiresAt, clusterID, userID)
 	if err != nil {
-		return fmt.Errorf(`store: unable to remove cluster: %v`, err)
+		return fmt.Errorf(`store: unable to update cluster expiry: %v`, err)
 	}
 
 	count, _ := result.RowsAffected()
 	if count == 0 {
-		return fmt.Errorf(`store: cluster not found`)
+		return ErrClusterNotFound
 	}
 
 	return nil
 }
 
-// RemoveExpiredClusters removes all expired clusters.
+// RemoveCluster removes a cluster and all its entry associations.
+// When markRead is true, the unread entries of the cluster are marked as read in the same transaction
+// and the number of entries marked is returned. The membership tags are removed in the same transaction.
+func (s *Storage) RemoveCluster(userID, clusterID int64, markRead bool) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	var exists bool
+	err = tx.QueryRow(`SELECT true FROM clusters WHERE id = $1 AND user_id = $2 FOR UPDATE`, clusterID, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return 0, ErrClusterNotFound
+	}
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf(`store: unable to fetch cluster: %v`, err)
+	}
+
+	if config.Opts.ClusteringMembershipTags() {
+		if _, err := tx.Exec(removeClusterMembershipTagsQuery, clusterID, model.TagSourceAuto, pq.Array([]int64(nil))); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf(`store: unable to remove cluster membership tags: %v`, err)
+		}
+	}
+
+	var marked int64
+	if markRead {
+		query := `
+			UPDATE
+				entries e
+			SET
+				status=$1,
+				changed_at=now()
+			FROM
+				cluster_entries ce, clusters c
+			WHERE
+				ce.entry_id = e.id AND c.id = ce.cluster_id AND
+				c.id=$2 AND c.user_id=$3 AND e.user_id=$3 AND e.status=$4
+		`
+		result, err := tx.Exec(query, model.EntryStatusRead, clusterID, userID, model.EntryStatusUnread)
+		if err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf(`store: unable to mark cluster entries as read: %v`, err)
+		}
+		marked, _ = result.RowsAffected()
+	}
+
+	if _, err := tx.Exec(`DELETE FROM clusters WHERE id = $1 AND user_id = $2`, clusterID, userID); err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf(`store: unable to remove cluster: %v`, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	return marked, nil
+}
+
+// RemoveExpiredClusters removes all expired clusters, along with their membership tags.
 func (s *Storage) RemoveExpiredClusters() (int64, error) {
-	query := `DELETE FROM clusters WHERE expires_at IS NOT NULL AND expires_at < NOW()`
-	result, err := s.db.Exec(query)
+	count, err := s.removeClusters(`expires_at IS NOT NULL AND expires_at < NOW()`)
 	if err != nil {
 		return 0, fmt.Errorf(`store: unable to remove expired clusters: %v`, err)
 	}
 
-	return result.RowsAffected()
+	return count, nil
 }
 
-// RemoveAllClusters removes all clusters for a user.
+// RemoveAllClusters removes all clusters for a user, along with their membership tags.
 func (s *Storage) RemoveAllClusters(userID int64) error {
-	query := `DELETE FROM clusters WHERE user_id = $1`
-	_, err := s.db.Exec(query, userID)
-	if err != nil {
+	if _, err := s.removeClusters(`user_id = $1`, userID); err != nil {
 		return fmt.Errorf(`store: unable to remove all clusters: %v`, err)
 	}
 
 	return nil
 }
 
+// removeClusters deletes the clusters matching the condition and returns their number.
+// When membership tags are enabled, the same statement removes the auto tags named after the deleted clusters
+// from their entries, unless another remaining cluster with the same name still contains the entry.
+func (s *Storage) removeClusters(condition string, args ...any) (int64, error) {
+	query := `
+		WITH removed AS (
+			DELETE FROM clusters WHERE ` + condition + `
+			RETURNING id
+		)
+		SELECT COUNT(*) FROM removed
+	`
+	if config.Opts.ClusteringMembershipTags() {
+		query = fmt.Sprintf(`
+			WITH removed AS (
+				DELETE FROM clusters WHERE %s
+				RETURNING id, user_id, name
+			), untagged AS (
+				DELETE FROM entry_tags et
+				USING removed c, cluster_entries ce, tags t
+				WHERE ce.cluster_id = c.id
+				  AND et.entry_id = ce.entry_id
+				  AND et.tag_id = t.id
+				  AND et.source = $%d
+				  AND t.user_id = c.user_id
+				  AND lower(t.name) = lower(c.name)
+				  AND NOT EXISTS (
+					SELECT 1
+					FROM cluster_entries other_ce
+					JOIN clusters other ON other.id = other_ce.cluster_id
+					WHERE other_ce.entry_id = et.entry_id
+					  AND other.user_id = c.user_id
+					  AND lower(other.name) = lower(c.name)
+					  AND other.id NOT IN (SELECT id FROM removed)
+				  )
+			)
+			SELECT COUNT(*) FROM removed
+		`, condition, len(args)+1)
+		args = append(args, model.TagSourceAuto)
+	}
+
+	var count int64
+	if err := s.db.QueryRow(query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// AllClusteredEntries returns the entries that belong to any cluster of the user, newest first.
+// Entries that are members of several clusters are returned only once.
+func (s *Storage) AllClusteredEntries(userID int64, limit, offset int) (model.Entries, error) {
+	builder := s.NewEntryQueryBuilder(userID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithClustered()
+	builder.WithSorting("published_at", "DESC")
+	builder.WithSorting("id", "DESC")
+	builder.WithLimit(limit)
+	builder.WithOffset(offset)
+
+	return builder.GetEntries()
+}
+
 // GetEntriesForClustering returns recent entries that can be clustered.
 func (s *Storage) GetEntriesForClustering(userID int64, limit int, maxAgeDays int) (model.Entries, error) {
+	return s.entriesForClustering(userID, limit, maxAgeDays, "")
+}
+
+// UnclusteredEntries returns recent entries that are not a member of any cluster yet.
+func (s *Storage) UnclusteredEntries(userID int64, maxAgeDays, limit int) (model.Entries, error) {
+	return s.entriesForClustering(userID, limit, maxAgeDays, unclusteredEntriesCondition)
+}
+
+// EmbeddedUnclusteredEntries returns recent entries having an embedding that are not a member of any cluster yet.
+// Entries without embedding can't be placed by the clusterer, so they don't take room in the limit.
+func (s *Storage) EmbeddedUnclusteredEntries(userID int64, limit, maxAgeDays int) (model.Entries, error) {
+	return s.entriesForClustering(userID, limit, maxAgeDays, unclusteredEntriesCondition+` AND e.embedding IS NOT NULL`)
+}
+
+const unclusteredEntriesCondition = ` AND NOT EXISTS (SELECT 1 FROM cluster_entries ce WHERE ce.entry_id = e.id)`
+
+func (s *Storage) entriesForClustering(userID int64, limit, maxAgeDays int, condition string) (model.Entries, error) {
 	query := `
 		SELECT
 			e.id, e.user_id, e.feed_id, e.title, e.url, e.published_at, e.content,
-			f.title as feed_title
+			e.embedding, e.embedding_normalized, f.title as feed_title
 		FROM entries e
 		JOIN feeds f ON e.feed_id = f.id
 		WHERE e.user_id = $1
 		  AND e.status != 'removed'
 		  AND e.published_at > NOW() - INTERVAL '1 day' * $2
-		ORDER BY e.published_at DESC
-		LIMIT $3
 	`
+	query += condition + ` ORDER BY e.published_at DESC LIMIT $3`
+
 	rows, err := s.db.Query(query, userID, maxAgeDays, limit)
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to fetch entries for clustering: %v`, err)
@@ -329,6 +1317,8 @@ func (s *Storage) GetEntriesForClustering(userID int64, limit int, maxAgeDays in
 			&entry.URL,
 			&entry.Date,
 			&entry.Content,
+			&entry.Embedding,
+			&entry.EmbeddingNormalized,
 			&entry.Feed.Title,
 		)
 		if err != nil {
@@ -341,10 +1331,41 @@ func (s *Storage) GetEntriesForClustering(userID int64, limit int, maxAgeDays in
 	return entries, nil
 }
 
+// EntriesAlreadyClustered reports which of the given entries already belong to a cluster created after the given date.
+func (s *Storage) EntriesAlreadyClustered(userID int64, entryIDs []int64, since time.Time) (map[int64]bool, error) {
+	result := make(map[int64]bool)
+	if len(entryIDs) == 0 {
+		return result, nil
+	}
+
+	query := `
+		SELECT DISTINCT ce.entry_id
+		FROM cluster_entries ce
+		JOIN clusters c ON c.id = ce.cluster_id
+		WHERE c.user_id = $1 AND ce.entry_id = ANY($2) AND c.created_at > $3
+	`
+	rows, err := s.db.Query(query, userID, pq.Array(entryIDs), since)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch clustered entries: %v`, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entryID int64
+		if err := rows.Scan(&entryID); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch clustered entry row: %v`, err)
+		}
+		result[entryID] = true
+	}
+
+	return result, nil
+}
+
 // GetEntryClusters returns all clusters that contain a specific entry.
 func (s *Storage) GetEntryClusters(userID, entryID int64) (model.Clusters, error) {
 	query := `
-		SELECT c.id, c.user_id, c.name, c.created_at, c.expires_at
+		SELECT c.id, c.user_id, c.name, c.source, c.created_at, c.expires_at,
+		       (SELECT COUNT(*) FROM cluster_entries other WHERE other.cluster_id = c.id) as entry_count
 		FROM clusters c
 		JOIN cluster_entries ce ON c.id = ce.cluster_id
 		WHERE ce.entry_id = $1 AND c.user_id = $2
@@ -361,24 +1382,27 @@ func (s *Storage) GetEntryClusters(userID, entryID int64) (model.Clusters, error
 	for rows.Next() {
 		var cluster model.Cluster
 		var expiresAt sql.NullTime
+		var entryCount int
 
-		if err := rows.Scan(&cluster.ID, &cluster.UserID, &cluster.Name, &cluster.CreatedAt, &expiresAt); err != nil {
+		if err := rows.Scan(&cluster.ID, &cluster.UserID, &cluster.Name, &cluster.Source, &cluster.CreatedAt, &expiresAt, &entryCount); err != nil {
 			return nil, fmt.Errorf(`store: unable to fetch cluster row: %v`, err)
 		}
 
 		if expiresAt.Valid {
 			cluster.ExpiresAt = &expiresAt.Time
 		}
+		cluster.EntryCount = &entryCount
 		clusters = append(clusters, &cluster)
 	}
 
 	return clusters, nil
 }
 
-// UpdateEntrySummary updates the summary for an entry.
-func (s *Storage) UpdateEntrySummary(entryID int64, summary string) error {
-	query := `UPDATE entries SET summary = $1, summarized_at = NOW() WHERE id = $2`
-	_, err := s.db.Exec(query, summary, entryID)
+// UpdateEntrySummary updates the summary for an entry, along with its format.
+// The current entry hash is recorded so the summary is not regenerated while the content is unchanged.
+func (s *Storage) UpdateEntrySummary(entryID int64, summary, format string) error {
+	query := `UPDATE entries SET summary = $1, summary_format = $2, summary_content_hash = hash, summarized_at = NOW() WHERE id = $3`
+	_, err := s.db.Exec(query, summary, format, entryID)
 	if err != nil {
 		return fmt.Errorf(`store: unable to update entry summary: %v`, err)
 	}
@@ -386,10 +1410,95 @@ func (s *Storage) UpdateEntrySummary(entryID int64, summary string) error {
 	return nil
 }
 
-// UpdateEntryEmbedding updates the embedding for an entry.
-func (s *Storage) UpdateEntryEmbedding(entryID int64, embedding []byte) error {
-	query := `UPDATE entries SET embedding = $1 WHERE id = $2`
-	_, err := s.db.Exec(query, embedding, entryID)
+// UpdateEntrySummariesBatch updates the summaries of multiple entries in a single transaction.
+// All summaries are expected to have the same format.
+func (s *Storage) UpdateEntrySummariesBatch(summaries map[int64]string, format string) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	stmt, err := tx.Prepare(`UPDATE entries SET summary = $1, summary_format = $2, summary_content_hash = hash, summarized_at = NOW() WHERE id = $3`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf(`store: unable to prepare statement: %v`, err)
+	}
+	defer stmt.Close()
+
+	for entryID, summary := range summaries {
+		if _, err := stmt.Exec(summary, format, entryID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf(`store: unable to update summary of entry #%d: %v`, entryID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	return nil
+}
+
+// EntryEmbedding returns the stored embedding of an entry, whether it is normalized and the name of the model
+// that computed it, when known. A nil embedding is returned when the entry doesn't exist or has no embedding.
+func (s *Storage) EntryEmbedding(userID, entryID int64) (data []byte, normalized bool, modelName string, err error) {
+	var nullModelName sql.NullString
+	query := `SELECT embedding, embedding_normalized, embedding_model FROM entries WHERE id = $1 AND user_id = $2 AND embedding IS NOT NULL`
+	err = s.db.QueryRow(query, entryID, userID).Scan(&data, &normalized, &nullModelName)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, false, "", nil
+	case err != nil:
+		return nil, false, "", fmt.Errorf(`store: unable to fetch embedding of entry #%d: %v`, entryID, err)
+	}
+
+	return data, normalized, nullModelName.String, nil
+}
+
+// EmbeddingDimension returns the dimension of the most recent embedding of a user computed by the given model,
+// or 0 when there is none. An empty model name matches the embeddings of unknown model.
+func (s *Storage) EmbeddingDimension(userID int64, modelName string) (int, error) {
+	var dimension int
+	query := `
+		SELECT length(embedding) / 4
+		FROM entries
+		WHERE user_id = $1 AND embedding IS NOT NULL AND embedding_model IS NOT DISTINCT FROM NULLIF($2, '')
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	err := s.db.QueryRow(query, userID, modelName).Scan(&dimension)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, nil
+	case err != nil:
+		return 0, fmt.Errorf(`store: unable to fetch embedding dimension: %v`, err)
+	}
+
+	return dimension, nil
+}
+
+// UpdateEntryEmbedding updates the embedding for an entry, along with the name of the model that computed it.
+// The vector is stored as given and flagged as not normalized. An empty model name is stored as unknown.
+func (s *Storage) UpdateEntryEmbedding(entryID int64, embedding []byte, modelName string) error {
+	query := `UPDATE entries SET embedding = $1, embedding_normalized = false, embedding_model = NULLIF($2, '') WHERE id = $3`
+	_, err := s.db.Exec(query, embedding, modelName, entryID)
+	if err != nil {
+		return fmt.Errorf(`store: unable to update entry embedding: %v`, err)
+	}
+
+	return nil
+}
+
+// UpdateEntryNormalizedEmbedding L2-normalizes the vector before storing it as the embedding of an entry,
+// so similarity computations can use a dot product instead of recomputing norms.
+// An empty model name is stored as unknown.
+func (s *Storage) UpdateEntryNormalizedEmbedding(entryID int64, vector []float32, modelName string) error {
+	query := `UPDATE entries SET embedding = $1, embedding_normalized = true, embedding_model = NULLIF($2, '') WHERE id = $3`
+	_, err := s.db.Exec(query, embedding.Encode(embedding.Normalize(vector)), modelName, entryID)
 	if err != nil {
 		return fmt.Errorf(`store: unable to update entry embedding: %v`, err)
 	}
@@ -397,37 +1506,145 @@ func (s *Storage) UpdateEntryEmbedding(entryID int64, embedding []byte) error {
 	return nil
 }
 
-// GetEntriesWithoutSummary returns entries that don't have a summary yet.
-func (s *Storage) GetEntriesWithoutSummary(userID int64, feedIDs []int64, limit int) (model.Entries, error) {
-	var query string
-	var rows *sql.Rows
-	var err error
+// RemoveEmbeddingsForRemovedEntries clears the embeddings of the removed entries of a user.
+// It returns the number of embeddings cleared.
+func (s *Storage) RemoveEmbeddingsForRemovedEntries(userID int64) (int64, error) {
+	query := `
+		UPDATE entries
+		SET embedding = NULL, embedding_normalized = false, embedding_model = NULL
+		WHERE user_id = $1 AND status = $2 AND embedding IS NOT NULL
+	`
+	result, err := s.db.Exec(query, userID, model.EntryStatusRemoved)
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to remove embeddings of removed entries: %v`, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to count removed embeddings: %v`, err)
+	}
+
+	return count, nil
+}
+
+// UpdateEntryEmbeddingsBatch updates the embeddings of multiple entries in a single transaction.
+func (s *Storage) UpdateEntryEmbeddingsBatch(embeddings map[int64][]byte) error {
+	if len(embeddings) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	stmt, err := tx.Prepare(`UPDATE entries SET embedding = $1, embedding_normalized = false, embedding_model = NULL WHERE id = $2`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf(`store: unable to prepare statement: %v`, err)
+	}
+	defer stmt.Close()
+
+	for entryID, data := range embeddings {
+		if _, err := stmt.Exec(data, entryID); err != nil {
+			tx.Rollback()
+			return fmt.Errorf(`store: unable to update embedding of entry #%d: %v`, entryID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	return nil
+}
+
+// GetEntriesWithoutSummary returns entries that don't have a summary yet,
+// or whose summary was generated for a different content hash.
+// Entries with less than minContentLength characters of content are skipped; 0 disables the filter.
+// When format is set, entries having a summary in another format are returned as well.
+func (s *Storage) GetEntriesWithoutSummary(userID int64, feedIDs []int64, limit int, minContentLength int, format string) (model.Entries, error) {
+	args := []any{userID, limit}
+	conditions := ""
+
+	if format == "" {
+		conditions += " AND (e.summary IS NULL OR e.summary_content_hash IS DISTINCT FROM e.hash)"
+	} else {
+		args = append(args, format)
+		conditions += fmt.Sprintf(
+			" AND (e.summary IS NULL OR e.summary_content_hash IS DISTINCT FROM e.hash OR e.summary_format IS DISTINCT FROM $%d)",
+			len(args),
+		)
+	}
 
 	if len(feedIDs) > 0 {
-		query = `
-			SELECT e.id, e.user_id, e.feed_id, e.title, e.url, e.content, e.published_at
+		args = append(args, pq.Array(feedIDs))
+		conditions += fmt.Sprintf(" AND e.feed_id = ANY($%d)", len(args))
+	}
+
+	if minContentLength > 0 {
+		args = append(args, minContentLength)
+		conditions += fmt.Sprintf(" AND length(e.content) >= $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT e.id, e.user_id, e.feed_id, e.title, e.url, e.content, e.published_at
+		FROM entries e
+		WHERE e.user_id = $1
+		  AND e.status != 'removed'
+		  %s
+		ORDER BY e.published_at DESC
+		LIMIT $2
+	`, conditions)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch entries without summary: %v`, err)
+	}
+	defer rows.Close()
+
+	entries := make(model.Entries, 0)
+	for rows.Next() {
+		var entry model.Entry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.FeedID,
+			&entry.Title,
+			&entry.URL,
+			&entry.Content,
+			&entry.Date,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch entry row: %v`, err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+// GetEntriesWithoutSummaryWithinTokenBudget returns the most recent entries without a summary,
+// or whose summary was generated for a different content hash, whose cumulative content length doesn't exceed maxTotalChars.
+// Entries are taken in order and the selection stops at the first entry that would exceed the budget,
+// so an entry longer than the whole budget is never returned.
+func (s *Storage) GetEntriesWithoutSummaryWithinTokenBudget(userID int64, maxTotalChars int, limit int) (model.Entries, error) {
+	query := `
+		SELECT id, user_id, feed_id, title, url, content, published_at
+		FROM (
+			SELECT
+				e.id, e.user_id, e.feed_id, e.title, e.url, e.content, e.published_at,
+				SUM(length(e.content)) OVER (ORDER BY e.published_at DESC, e.id DESC) as running_total
 			FROM entries e
 			WHERE e.user_id = $1
-			  AND e.feed_id = ANY($2)
 			  AND e.status != 'removed'
-			  AND e.summary IS NULL
-			ORDER BY e.published_at DESC
+			  AND (e.summary IS NULL OR e.summary_content_hash IS DISTINCT FROM e.hash)
+			ORDER BY e.published_at DESC, e.id DESC
 			LIMIT $3
-		`
-		rows, err = s.db.Query(query, userID, pq.Array(feedIDs), limit)
-	} else {
-		query = `
-			SELECT e.id, e.user_id, e.feed_id, e.title, e.url, e.content, e.published_at
-			FROM entries e
-			WHERE e.user_id = $1
-			  AND e.status != 'removed'
-			  AND e.summary IS NULL
-			ORDER BY e.published_at DESC
-			LIMIT $2
-		`
-		rows, err = s.db.Query(query, userID, limit)
-	}
-
+		) candidates
+		WHERE running_total <= $2
+		ORDER BY published_at DESC, id DESC
+	`
+	rows, err := s.db.Query(query, userID, maxTotalChars, limit)
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to fetch entries without summary: %v`, err)
 	}
@@ -455,18 +1672,51 @@ func (s *Storage) GetEntriesWithoutSummary(userID int64, feedIDs []int64, limit
 }
 
 // GetEntriesWithoutEmbedding returns entries that don't have an embedding yet.
-func (s *Storage) GetEntriesWithoutEmbedding(userID int64, limit int, maxAgeDays int) (model.Entries, error) {
-	query := `
+// Entries with less than minContentLength characters of content are skipped; 0 disables the filter.
+func (s *Storage) GetEntriesWithoutEmbedding(userID int64, limit int, maxAgeDays int, minContentLength int) (model.Entries, error) {
+	return s.entriesWithoutEmbedding(userID, limit, maxAgeDays, minContentLength, nil)
+}
+
+// GetEntriesWithoutEmbeddingForBackfill returns entries that don't have an embedding yet,
+// starting after the last entry recorded in the embedding backfill state of the user.
+// The caller is expected to record its progress with UpdateEmbeddingBackfillState.
+func (s *Storage) GetEntriesWithoutEmbeddingForBackfill(userID int64, limit int, maxAgeDays int, minContentLength int) (model.Entries, error) {
+	state, err := s.EmbeddingBackfillState(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.entriesWithoutEmbedding(userID, limit, maxAgeDays, minContentLength, state)
+}
+
+// entriesWithoutEmbedding returns the newest entries without embedding,
+// only considering the entries older than the backfill position when a state is given.
+func (s *Storage) entriesWithoutEmbedding(userID int64, limit int, maxAgeDays int, minContentLength int, state *model.EmbeddingBackfillState) (model.Entries, error) {
+	args := []any{userID, maxAgeDays, limit}
+	conditions := ""
+
+	if state != nil {
+		args = append(args, state.LastPublishedAt, state.LastEntryID)
+		conditions += fmt.Sprintf(" AND (e.published_at, e.id) < ($%d, $%d)", len(args)-1, len(args))
+	}
+
+	if minContentLength > 0 {
+		args = append(args, minContentLength)
+		conditions += fmt.Sprintf(" AND length(e.content) >= $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
 		SELECT e.id, e.user_id, e.feed_id, e.title, e.url, e.content, e.published_at
 		FROM entries e
 		WHERE e.user_id = $1
 		  AND e.status != 'removed'
 		  AND e.embedding IS NULL
 		  AND e.published_at > NOW() - INTERVAL '1 day' * $2
-		ORDER BY e.published_at DESC
+		  %s
+		ORDER BY e.published_at DESC, e.id DESC
 		LIMIT $3
-	`
-	rows, err := s.db.Query(query, userID, maxAgeDays, limit)
+	`, conditions)
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to fetch entries without embedding: %v`, err)
 	}
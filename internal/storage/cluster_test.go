@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUniqueEntryIDs(t *testing.T) {
+	result := uniqueEntryIDs([]int64{3, 1, 3, 2, 1})
+	expected := []int64{3, 1, 2}
+
+	if !slices.Equal(result, expected) {
+		t.Errorf(`Unexpected entry IDs, got %v instead of %v`, result, expected)
+	}
+
+	if result := uniqueEntryIDs(nil); len(result) != 0 {
+		t.Errorf(`Expected no entry IDs, got %v`, result)
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage // import "miniflux.app/v2/internal/storage"
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"miniflux.app/v2/internal/model"
+)
+
+// EmbeddingBackfillState returns the embedding backfill progress of a user, or nil if no backfill is in progress.
+func (s *Storage) EmbeddingBackfillState(userID int64) (*model.EmbeddingBackfillState, error) {
+	var state model.EmbeddingBackfillState
+
+	query := `
+		SELECT user_id, last_entry_id, last_published_at, updated_at
+		FROM embedding_backfill_state
+		WHERE user_id=$1
+	`
+	err := s.db.QueryRow(query, userID).Scan(&state.UserID, &state.LastEntryID, &state.LastPublishedAt, &state.UpdatedAt)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf(`store: unable to fetch embedding backfill state: %v`, err)
+	default:
+		return &state, nil
+	}
+}
+
+// UpdateEmbeddingBackfillState records the last entry processed by the embedding backfill of a user.
+func (s *Storage) UpdateEmbeddingBackfillState(userID, lastEntryID int64, lastPublishedAt time.Time) error {
+	query := `
+		INSERT INTO embedding_backfill_state (user_id, last_entry_id, last_published_at, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			last_entry_id = EXCLUDED.last_entry_id,
+			last_published_at = EXCLUDED.last_published_at,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := s.db.Exec(query, userID, lastEntryID, lastPublishedAt); err != nil {
+		return fmt.Errorf(`store: unable to update embedding backfill state: %v`, err)
+	}
+
+	return nil
+}
+
+// ResetEmbeddingBackfillState forgets the embedding backfill progress of a user,
+// so the next backfill starts again from the newest entries.
+func (s *Storage) ResetEmbeddingBackfillState(userID int64) error {
+	query := `DELETE FROM embedding_backfill_state WHERE user_id=$1`
+	if _, err := s.db.Exec(query, userID); err != nil {
+		return fmt.Errorf(`store: unable to reset embedding backfill state: %v`, err)
+	}
+
+	return nil
+}
@@ -434,6 +434,22 @@ func (s *Storage) ArchiveEntries(status string, interval time.Duration, limit in
 	return count, nil
 }
 
+// EntryIDsExist checks if all the given entries exist for a user.
+func (s *Storage) EntryIDsExist(userID int64, entryIDs []int64) bool {
+	uniqueIDs := make(map[int64]struct{}, len(entryIDs))
+	for _, entryID := range entryIDs {
+		uniqueIDs[entryID] = struct{}{}
+	}
+
+	var count int
+	query := `SELECT count(*) FROM entries WHERE user_id=$1 AND id=ANY($2)`
+	if err := s.db.QueryRow(query, userID, pq.Array(entryIDs)).Scan(&count); err != nil {
+		return false
+	}
+
+	return count == len(uniqueIDs)
+}
+
 // SetEntriesStatus update the status of the given list of entries.
 func (s *Storage) SetEntriesStatus(userID int64, entryIDs []int64, status string) error {
 	// Entries that have the model.EntryStatusRemoved status are immutable.
@@ -496,6 +512,8 @@ func (s *Storage) SetEntriesStarredState(userID int64, entryIDs []int64, starred
 		return errors.New(`store: nothing has been updated`)
 	}
 
+	s.applyStarredTag(userID, entryIDs)
+
 	return nil
 }
 
@@ -516,9 +534,30 @@ func (s *Storage) ToggleStarred(userID int64, entryID int64) error {
 		return errors.New(`store: nothing has been updated`)
 	}
 
+	s.applyStarredTag(userID, []int64{entryID})
+
 	return nil
 }
 
+// applyStarredTag synchronizes the starred tag configured by the user with the starred state of the entries.
+// Failures are only logged since the starred state itself has already been updated.
+func (s *Storage) applyStarredTag(userID int64, entryIDs []int64) {
+	var tagName string
+	if err := s.db.QueryRow(`SELECT starred_tag_name FROM users WHERE id=$1`, userID).Scan(&tagName); err != nil || tagName == "" {
+		return
+	}
+
+	for _, entryID := range entryIDs {
+		if err := s.TagOnStar(userID, entryID, tagName); err != nil {
+			slog.Error("Unable to apply the starred tag",
+				slog.Int64("user_id", userID),
+				slog.Int64("entry_id", entryID),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
 // FlushHistory changes all entries with the status "read" to "removed".
 func (s *Storage) FlushHistory(userID int64) error {
 	query := `
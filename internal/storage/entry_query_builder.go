@@ -180,6 +180,36 @@ func (e *EntryQueryBuilder) WithEntryTagID(tagID int64) *EntryQueryBuilder {
 	return e
 }
 
+// WithEntryTagIDAndSource filter by entry-level tag ID applied with the given source (manual or auto).
+// Any source matches when source is empty.
+func (e *EntryQueryBuilder) WithEntryTagIDAndSource(tagID int64, source string) *EntryQueryBuilder {
+	if source != model.TagSourceManual && source != model.TagSourceAuto {
+		return e.WithEntryTagID(tagID)
+	}
+
+	if tagID > 0 {
+		e.conditions = append(e.conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM entry_tags et WHERE et.entry_id = e.id AND et.tag_id = $%d AND et.source = $%d)",
+			len(e.args)+1,
+			len(e.args)+2,
+		))
+		e.args = append(e.args, tagID, source)
+	}
+	return e
+}
+
+// WithoutEntryTagID filter out entries having the given entry-level tag ID.
+func (e *EntryQueryBuilder) WithoutEntryTagID(tagID int64) *EntryQueryBuilder {
+	if tagID > 0 {
+		e.conditions = append(e.conditions, fmt.Sprintf(
+			"NOT EXISTS (SELECT 1 FROM entry_tags et WHERE et.entry_id = e.id AND et.tag_id = $%d)",
+			len(e.args)+1,
+		))
+		e.args = append(e.args, tagID)
+	}
+	return e
+}
+
 // WithEntryTagIDs filter by multiple entry-level tag IDs.
 func (e *EntryQueryBuilder) WithEntryTagIDs(tagIDs []int64) *EntryQueryBuilder {
 	if len(tagIDs) > 0 {
@@ -192,6 +222,49 @@ func (e *EntryQueryBuilder) WithEntryTagIDs(tagIDs []int64) *EntryQueryBuilder {
 	return e
 }
 
+// WithoutAnyTag filter entries that don't have any entry-level tag.
+func (e *EntryQueryBuilder) WithoutAnyTag() *EntryQueryBuilder {
+	e.conditions = append(e.conditions, "NOT EXISTS (SELECT 1 FROM entry_tags et WHERE et.entry_id = e.id)")
+	return e
+}
+
+// WithoutReviewedTags filter entries whose tags have not been marked as reviewed.
+func (e *EntryQueryBuilder) WithoutReviewedTags() *EntryQueryBuilder {
+	e.conditions = append(e.conditions, "e.tags_reviewed_at IS NULL")
+	return e
+}
+
+// WithEntryTagSource filter entries having at least one entry-level tag with the given source (manual or auto).
+func (e *EntryQueryBuilder) WithEntryTagSource(source string) *EntryQueryBuilder {
+	switch source {
+	case model.TagSourceManual, model.TagSourceAuto:
+		e.conditions = append(e.conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM entry_tags et WHERE et.entry_id = e.id AND et.source = $%d)",
+			len(e.args)+1,
+		))
+		e.args = append(e.args, source)
+	}
+	return e
+}
+
+// WithClusterID filter by cluster membership (from cluster_entries table).
+func (e *EntryQueryBuilder) WithClusterID(clusterID int64) *EntryQueryBuilder {
+	if clusterID > 0 {
+		e.conditions = append(e.conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM cluster_entries ce WHERE ce.entry_id = e.id AND ce.cluster_id = $%d)",
+			len(e.args)+1,
+		))
+		e.args = append(e.args, clusterID)
+	}
+	return e
+}
+
+// WithClustered filter entries that belong to at least one cluster.
+func (e *EntryQueryBuilder) WithClustered() *EntryQueryBuilder {
+	e.conditions = append(e.conditions, "EXISTS (SELECT 1 FROM cluster_entries ce WHERE ce.entry_id = e.id)")
+	return e
+}
+
 // WithSummary filter entries that have a summary.
 func (e *EntryQueryBuilder) WithSummary() *EntryQueryBuilder {
 	e.conditions = append(e.conditions, "e.summary IS NOT NULL AND e.summary != ''")
@@ -200,7 +273,19 @@ func (e *EntryQueryBuilder) WithSummary() *EntryQueryBuilder {
 
 // WithoutSummary filter entries that don't have a summary.
 func (e *EntryQueryBuilder) WithoutSummary() *EntryQueryBuilder {
-	e.conditions = append(e.conditions, "e.summary IS NULL OR e.summary = ''")
+	e.conditions = append(e.conditions, "(e.summary IS NULL OR e.summary = '')")
+	return e
+}
+
+// WithEmbedding filter entries that have an embedding.
+func (e *EntryQueryBuilder) WithEmbedding() *EntryQueryBuilder {
+	e.conditions = append(e.conditions, "e.embedding IS NOT NULL")
+	return e
+}
+
+// WithoutEmbedding filter entries that don't have an embedding.
+func (e *EntryQueryBuilder) WithoutEmbedding() *EntryQueryBuilder {
+	e.conditions = append(e.conditions, "e.embedding IS NULL")
 	return e
 }
 
@@ -4,24 +4,57 @@
 package storage // import "miniflux.app/v2/internal/storage"
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"slices"
 
 	"github.com/lib/pq"
+	"miniflux.app/v2/internal/config"
 	"miniflux.app/v2/internal/model"
 )
 
+// ErrEntryTagLimitReached is returned when an entry already has the maximum number of tags allowed.
+var ErrEntryTagLimitReached = errors.New("store: maximum number of tags reached for this entry")
+
+// maxSimilarEntriesForTagSuggestions is the number of similar entries whose tags are suggested.
+const maxSimilarEntriesForTagSuggestions = 10
+
 // AddTagToEntry adds a tag to an entry.
+// Auto tags are silently skipped for entries of feeds with auto-tagging disabled.
+// ErrEntryTagLimitReached is returned when a new association would exceed the configured limits:
+// manual tags are only bound by the overall limit, auto tags are also bound by the auto-only limit.
 func (s *Storage) AddTagToEntry(userID, entryID, tagID int64, source string) error {
-	// Verify entry belongs to user
-	var exists bool
-	err := s.db.QueryRow(`SELECT true FROM entries WHERE id=$1 AND user_id=$2`, entryID, userID).Scan(&exists)
+	tx, err := s.db.Begin()
 	if err != nil {
+		return fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	// Verify entry belongs to user, the row lock serializes the tag additions to the entry.
+	var exists, autoTaggingDisabled bool
+	query := `
+		SELECT true, f.disable_auto_tagging
+		FROM entries e
+		JOIN feeds f ON f.id = e.feed_id
+		WHERE e.id=$1 AND e.user_id=$2
+		FOR UPDATE OF e
+	`
+	err = tx.QueryRow(query, entryID, userID).Scan(&exists, &autoTaggingDisabled)
+	if err != nil {
+		tx.Rollback()
 		return fmt.Errorf(`store: entry #%d not found for user #%d: %v`, entryID, userID, err)
 	}
 
+	if source == model.TagSourceAuto && autoTaggingDisabled {
+		tx.Rollback()
+		return nil
+	}
+
 	// Verify tag belongs to user
-	err = s.db.QueryRow(`SELECT true FROM tags WHERE id=$1 AND user_id=$2`, tagID, userID).Scan(&exists)
+	err = tx.QueryRow(`SELECT true FROM tags WHERE id=$1 AND user_id=$2`, tagID, userID).Scan(&exists)
 	if err != nil {
+		tx.Rollback()
 		return fmt.Errorf(`store: tag #%d not found for user #%d: %v`, tagID, userID, err)
 	}
 
@@ -29,17 +62,46 @@ func (s *Storage) AddTagToEntry(userID, entryID, tagID int64, source string) err
 		source = model.TagSourceManual
 	}
 
-	query := `
+	// Updating the source of an existing association never adds a new row, so the limits don't apply.
+	query = `
 		INSERT INTO entry_tags (entry_id, tag_id, source)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (entry_id, tag_id) DO UPDATE SET source = $3
+		SELECT $1, $2, $3::tag_source
+		WHERE EXISTS (SELECT 1 FROM entry_tags et WHERE et.entry_id = $1 AND et.tag_id = $2)
+		   OR (
+			(SELECT COUNT(*) FROM entry_tags et WHERE et.entry_id = $1) < $5
+			AND ($3 <> $4 OR (SELECT COUNT(*) FROM entry_tags et WHERE et.entry_id = $1 AND et.source = $4) < $6)
+		   )
+		ON CONFLICT (entry_id, tag_id) DO UPDATE SET source = EXCLUDED.source
 	`
-	_, err = s.db.Exec(query, entryID, tagID, source)
+	result, err := tx.Exec(query,
+		entryID,
+		tagID,
+		source,
+		model.TagSourceAuto,
+		config.Opts.TagsMaxPerEntry(),
+		config.Opts.TagsMaxAutoPerEntry(),
+	)
 	if err != nil {
+		tx.Rollback()
 		return fmt.Errorf(`store: unable to add tag #%d to entry #%d: %v`, tagID, entryID, err)
 	}
 
-	return nil
+	count, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf(`store: unable to add tag #%d to entry #%d: %v`, tagID, entryID, err)
+	}
+
+	if count == 0 {
+		tx.Rollback()
+		return ErrEntryTagLimitReached
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	return s.refreshTagExpiry(tagID, source)
 }
 
 // AddTagsToEntry adds multiple tags to an entry.
@@ -64,8 +126,13 @@ func (s *Storage) AddTagToEntryByName(userID, entryID int64, tagName, source str
 
 // AddTagsToEntryByName adds multiple tags to an entry by name, creating tags if needed.
 func (s *Storage) AddTagsToEntryByName(userID, entryID int64, tagNames []string, source string) error {
-	for _, tagName := range tagNames {
-		if err := s.AddTagToEntryByName(userID, entryID, tagName, source); err != nil {
+	tags, err := s.GetOrCreateTags(userID, tagNames)
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if err := s.AddTagToEntry(userID, entryID, tag.ID, source); err != nil {
 			return err
 		}
 	}
@@ -111,9 +178,15 @@ func (s *Storage) RemoveAllTagsFromEntry(userID, entryID int64) error {
 // GetEntryTags returns all tags for an entry.
 func (s *Storage) GetEntryTags(userID, entryID int64) (model.EntryTags, error) {
 	query := `
-		SELECT et.entry_id, et.tag_id, et.source, et.created_at, t.name
+		SELECT et.entry_id, et.tag_id, et.source, et.created_at, t.name, COALESCE(counts.entry_count, 0)
 		FROM entry_tags et
 		JOIN tags t ON et.tag_id = t.id
+		LEFT JOIN (
+			SELECT tag_id, COUNT(*) AS entry_count
+			FROM entry_tags
+			WHERE tag_id IN (SELECT tag_id FROM entry_tags WHERE entry_id = $1)
+			GROUP BY tag_id
+		) counts ON counts.tag_id = et.tag_id
 		WHERE et.entry_id = $1 AND t.user_id = $2
 		ORDER BY t.name ASC
 	`
@@ -126,25 +199,29 @@ func (s *Storage) GetEntryTags(userID, entryID int64) (model.EntryTags, error) {
 	entryTags := make(model.EntryTags, 0)
 	for rows.Next() {
 		var et model.EntryTag
-		if err := rows.Scan(&et.EntryID, &et.TagID, &et.Source, &et.CreatedAt, &et.TagName); err != nil {
+		var entryCount int
+		if err := rows.Scan(&et.EntryID, &et.TagID, &et.Source, &et.CreatedAt, &et.TagName, &entryCount); err != nil {
 			return nil, fmt.Errorf(`store: unable to fetch entry tag row: %v`, err)
 		}
+		et.EntryCount = &entryCount
 		entryTags = append(entryTags, &et)
 	}
 
 	return entryTags, nil
 }
 
-// GetEntriesWithTag returns entry IDs that have a specific tag.
-func (s *Storage) GetEntriesWithTag(userID, tagID int64) ([]int64, error) {
-	query := `
+// entriesWithTagQuery selects the entries having the tag $1 of the user $2, newest first.
+const entriesWithTagQuery = `
 		SELECT et.entry_id
 		FROM entry_tags et
 		JOIN entries e ON et.entry_id = e.id
 		WHERE et.tag_id = $1 AND e.user_id = $2
-		ORDER BY e.published_at DESC
+		ORDER BY e.published_at DESC, e.id DESC
 	`
-	rows, err := s.db.Query(query, tagID, userID)
+
+// GetEntriesWithTag returns entry IDs that have a specific tag.
+func (s *Storage) GetEntriesWithTag(userID, tagID int64) ([]int64, error) {
+	rows, err := s.db.Query(entriesWithTagQuery, tagID, userID)
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to fetch entries with tag: %v`, err)
 	}
@@ -177,9 +254,80 @@ func (s *Storage) ConfirmAutoTag(userID, entryID, tagID int64) error {
 		return fmt.Errorf(`store: unable to confirm tag: %v`, err)
 	}
 
+	return s.refreshTagExpiry(tagID, model.TagSourceManual)
+}
+
+// RevertTagToAuto changes a confirmed tag back to auto-generated, so the entry is reviewed again.
+func (s *Storage) RevertTagToAuto(userID, entryID, tagID int64) error {
+	// Verify entry belongs to user
+	var exists bool
+	err := s.db.QueryRow(`SELECT true FROM entries WHERE id=$1 AND user_id=$2`, entryID, userID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf(`store: entry #%d not found for user #%d: %v`, entryID, userID, err)
+	}
+
+	query := `UPDATE entry_tags SET source=$1 WHERE entry_id=$2 AND tag_id=$3 AND source=$4`
+	result, err := s.db.Exec(query, model.TagSourceAuto, entryID, tagID, model.TagSourceManual)
+	if err != nil {
+		return fmt.Errorf(`store: unable to revert tag to auto: %v`, err)
+	}
+
+	if count, _ := result.RowsAffected(); count == 0 {
+		return nil
+	}
+
+	if _, err := s.db.Exec(`UPDATE entries SET tags_reviewed_at = NULL WHERE id = $1`, entryID); err != nil {
+		return fmt.Errorf(`store: unable to reset the tags review date of entry #%d: %v`, entryID, err)
+	}
+
+	return s.refreshTagExpiry(tagID, model.TagSourceAuto)
+}
+
+// CountEntriesWithPendingAutoTags returns the number of entries having at least one unconfirmed auto tag.
+func (s *Storage) CountEntriesWithPendingAutoTags(userID int64) (int, error) {
+	builder := s.NewEntryQueryBuilder(userID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithEntryTagSource(model.TagSourceAuto)
+	builder.WithoutReviewedTags()
+
+	return builder.CountEntries()
+}
+
+// MarkEntryTagsReviewed removes an entry from the review queue, whether or not its auto tags were confirmed.
+func (s *Storage) MarkEntryTagsReviewed(userID, entryID int64) error {
+	query := `UPDATE entries SET tags_reviewed_at = now() WHERE id = $1 AND user_id = $2`
+	if _, err := s.db.Exec(query, entryID, userID); err != nil {
+		return fmt.Errorf(`store: unable to mark tags of entry #%d as reviewed: %v`, entryID, err)
+	}
+
 	return nil
 }
 
+// ConfirmAllAutoTagsForUser changes every auto-generated tag of the user to manual and returns the number of confirmed tags.
+// The expiration date is cleared in the same statement, only for the tags having a confirmed association.
+func (s *Storage) ConfirmAllAutoTagsForUser(userID int64) (int64, error) {
+	query := `
+		WITH confirmed AS (
+			UPDATE entry_tags
+			SET source = $1
+			WHERE source = $2
+			AND tag_id IN (SELECT id FROM tags WHERE user_id = $3)
+			RETURNING tag_id
+		), cleared AS (
+			UPDATE tags
+			SET expires_at = NULL
+			WHERE id IN (SELECT tag_id FROM confirmed) AND expires_at IS NOT NULL
+		)
+		SELECT COUNT(*) FROM confirmed
+	`
+	var count int64
+	if err := s.db.QueryRow(query, model.TagSourceManual, model.TagSourceAuto, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf(`store: unable to confirm auto tags: %v`, err)
+	}
+
+	return count, nil
+}
+
 // GetAutoTagsForEntry returns only auto-generated tags for an entry.
 func (s *Storage) GetAutoTagsForEntry(userID, entryID int64) (model.EntryTags, error) {
 	query := `
@@ -207,6 +355,61 @@ func (s *Storage) GetAutoTagsForEntry(userID, entryID int64) (model.EntryTags, e
 	return entryTags, nil
 }
 
+// EntriesWithPendingAutoTags returns the entries having at least one auto tag waiting for review.
+// The pending auto tags of each entry are loaded in EntryTags.
+func (s *Storage) EntriesWithPendingAutoTags(userID int64, limit, offset int) (model.Entries, error) {
+	builder := s.NewEntryQueryBuilder(userID)
+	builder.WithoutStatus(model.EntryStatusRemoved)
+	builder.WithEntryTagSource(model.TagSourceAuto)
+	builder.WithoutReviewedTags()
+	builder.WithSorting("published_at", "DESC")
+	builder.WithSorting("id", "DESC")
+	builder.WithLimit(limit)
+	builder.WithOffset(offset)
+
+	entries, err := builder.GetEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return entries, nil
+	}
+
+	entryIDs := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		entryIDs = append(entryIDs, entry.ID)
+	}
+
+	query := `
+		SELECT et.entry_id, et.tag_id, et.source, et.created_at, t.name
+		FROM entry_tags et
+		JOIN tags t ON et.tag_id = t.id
+		WHERE t.user_id = $1 AND et.entry_id = ANY($2) AND et.source = $3
+		ORDER BY t.name ASC
+	`
+	rows, err := s.db.Query(query, userID, pq.Array(entryIDs), model.TagSourceAuto)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch pending auto tags: %v`, err)
+	}
+	defer rows.Close()
+
+	autoTags := make(map[int64]model.EntryTags, len(entries))
+	for rows.Next() {
+		var et model.EntryTag
+		if err := rows.Scan(&et.EntryID, &et.TagID, &et.Source, &et.CreatedAt, &et.TagName); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch entry tag row: %v`, err)
+		}
+		autoTags[et.EntryID] = append(autoTags[et.EntryID], &et)
+	}
+
+	for _, entry := range entries {
+		entry.EntryTags = autoTags[entry.ID]
+	}
+
+	return entries, nil
+}
+
 // RemoveAutoTagsFromEntry removes all auto-generated tags from an entry.
 func (s *Storage) RemoveAutoTagsFromEntry(userID, entryID int64) error {
 	// Get tag IDs for user's tags first
@@ -223,21 +426,74 @@ func (s *Storage) RemoveAutoTagsFromEntry(userID, entryID int64) error {
 	return nil
 }
 
+// countEntriesWithTagQuery counts the entries having the tag $1 of the user $2.
+const countEntriesWithTagQuery = `
+		SELECT COUNT(et.entry_id)
+		FROM entry_tags et
+		JOIN tags t ON t.id = et.tag_id
+		WHERE et.tag_id = $1 AND t.user_id = $2
+	`
+
 // CountEntriesWithTag returns the number of entries with a specific tag.
+// Tags are only applied to entries of their owner, so checking the tag ownership is enough
+// and the count can be answered from the entry_tags index without reading the entries.
 func (s *Storage) CountEntriesWithTag(userID, tagID int64) (int, error) {
 	var count int
+	err := s.db.QueryRow(countEntriesWithTagQuery, tagID, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to count entries with tag: %v`, err)
+	}
+
+	return count, nil
+}
+
+// CountEntryTagsBySource returns the number of manual and automatic tag associations of a user.
+func (s *Storage) CountEntryTagsBySource(userID int64) (manual int, auto int, err error) {
 	query := `
-		SELECT COUNT(et.entry_id)
+		SELECT
+			COUNT(*) FILTER (WHERE et.source = $2),
+			COUNT(*) FILTER (WHERE et.source = $3)
 		FROM entry_tags et
-		JOIN entries e ON et.entry_id = e.id
-		WHERE et.tag_id = $1 AND e.user_id = $2
+		JOIN tags t ON t.id = et.tag_id
+		WHERE t.user_id = $1
 	`
-	err := s.db.QueryRow(query, tagID, userID).Scan(&count)
+	err = s.db.QueryRow(query, userID, model.TagSourceManual, model.TagSourceAuto).Scan(&manual, &auto)
 	if err != nil {
-		return 0, fmt.Errorf(`store: unable to count entries with tag: %v`, err)
+		return 0, 0, fmt.Errorf(`store: unable to count entry tags by source: %v`, err)
 	}
 
-	return count, nil
+	return manual, auto, nil
+}
+
+// GetEntryTagsForEntries returns the tags applied to the given entries, grouped by entry ID.
+func (s *Storage) GetEntryTagsForEntries(userID int64, entryIDs []int64) (map[int64]model.EntryTags, error) {
+	if len(entryIDs) == 0 {
+		return make(map[int64]model.EntryTags), nil
+	}
+
+	query := `
+		SELECT et.entry_id, et.tag_id, et.source, et.created_at, t.name
+		FROM entry_tags et
+		JOIN tags t ON et.tag_id = t.id
+		WHERE t.user_id = $1 AND et.entry_id = ANY($2)
+		ORDER BY et.entry_id, t.name
+	`
+	rows, err := s.db.Query(query, userID, pq.Array(entryIDs))
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch entry tags for entries: %v`, err)
+	}
+	defer rows.Close()
+
+	result := make(map[int64]model.EntryTags)
+	for rows.Next() {
+		var et model.EntryTag
+		if err := rows.Scan(&et.EntryID, &et.TagID, &et.Source, &et.CreatedAt, &et.TagName); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch entry tag row: %v`, err)
+		}
+		result[et.EntryID] = append(result[et.EntryID], &et)
+	}
+
+	return result, nil
 }
 
 // GetTagNamesForEntries returns tag names for multiple entries (for bulk display).
@@ -271,3 +527,278 @@ func (s *Storage) GetTagNamesForEntries(userID int64, entryIDs []int64) (map[int
 
 	return result, nil
 }
+
+// SuggestTagsFromFeedHistory proposes the tags most often applied to the other entries of the feed of the given entry.
+// Tags already applied to the entry and tags scoped to another category are never suggested.
+func (s *Storage) SuggestTagsFromFeedHistory(userID, entryID int64) ([]model.Tag, error) {
+	return s.suggestTags(userID, entryID, `e.feed_id = current.feed_id`)
+}
+
+// SuggestTagsForEntry combines the tags suggested from the feed history of the entry with the tags
+// applied to the entries having the most similar embedding. Feed history suggestions come first.
+// The tags of similar entries are ranked by how many of them carry the tag and by how many entries
+// the tag has overall, popularityWeight (between 0 and 1) being the share given to the latter.
+func (s *Storage) SuggestTagsForEntry(userID, entryID int64, limit int, popularityWeight float64) ([]model.Tag, error) {
+	tags, err := s.SuggestTagsFromFeedHistory(userID, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	similarEntries, err := s.FindSimilarEntries(userID, entryID, maxSimilarEntriesForTagSuggestions)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(similarEntries) > 0 {
+		similarEntryIDs := make([]int64, 0, len(similarEntries))
+		for _, scoredEntry := range similarEntries {
+			similarEntryIDs = append(similarEntryIDs, scoredEntry.Entry.ID)
+		}
+
+		similarTags, err := s.suggestTags(userID, entryID, `e.id = ANY($3)`, pq.Array(similarEntryIDs))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.rankTagsByPopularity(userID, similarTags, similarEntryIDs, popularityWeight); err != nil {
+			return nil, err
+		}
+
+		for _, tag := range similarTags {
+			if !slices.ContainsFunc(tags, func(t model.Tag) bool { return t.ID == tag.ID }) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	if limit > 0 && len(tags) > limit {
+		tags = tags[:limit]
+	}
+
+	return tags, nil
+}
+
+// rankTagsByPopularity sorts the tags suggested from the given neighbor entries by tagSuggestionScore.
+func (s *Storage) rankTagsByPopularity(userID int64, tags []model.Tag, neighborIDs []int64, popularityWeight float64) error {
+	neighborTags, err := s.GetEntryTagsForEntries(userID, neighborIDs)
+	if err != nil {
+		return err
+	}
+
+	frequencies := make(map[int64]int)
+	for _, entryTags := range neighborTags {
+		for _, entryTag := range entryTags {
+			frequencies[entryTag.TagID]++
+		}
+	}
+
+	tagsWithCount, err := s.TagsWithCount(userID, 0, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	entryCounts := make(map[int64]int, len(tagsWithCount))
+	maxEntryCount := 0
+	for _, tag := range tagsWithCount {
+		if tag.EntryCount != nil {
+			entryCounts[tag.ID] = *tag.EntryCount
+			maxEntryCount = max(maxEntryCount, *tag.EntryCount)
+		}
+	}
+
+	scores := make(map[int64]float64, len(tags))
+	for _, tag := range tags {
+		scores[tag.ID] = tagSuggestionScore(frequencies[tag.ID], len(neighborIDs), entryCounts[tag.ID], maxEntryCount, popularityWeight)
+	}
+
+	slices.SortStableFunc(tags, func(a, b model.Tag) int {
+		switch {
+		case scores[a.ID] > scores[b.ID]:
+			return -1
+		case scores[a.ID] < scores[b.ID]:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return nil
+}
+
+// tagSuggestionScore mixes the share of neighbors having the tag with the number of entries of the tag,
+// relative to the most used tag on a logarithmic scale so a few very popular tags don't flatten the others.
+func tagSuggestionScore(frequency, neighbors, entryCount, maxEntryCount int, popularityWeight float64) float64 {
+	var frequencyScore, popularityScore float64
+	if neighbors > 0 {
+		frequencyScore = float64(frequency) / float64(neighbors)
+	}
+	if maxEntryCount > 0 {
+		popularityScore = math.Log1p(float64(entryCount)) / math.Log1p(float64(maxEntryCount))
+	}
+
+	return (1-popularityWeight)*frequencyScore + popularityWeight*popularityScore
+}
+
+// suggestTags returns the tags applied to the entries matching the condition, most used first.
+// The condition can reference the tagged entries as "e" and the entry receiving the suggestions as "current".
+func (s *Storage) suggestTags(userID, entryID int64, entriesCondition string, args ...any) ([]model.Tag, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, t.name, t.description, t.pinned, t.created_at, t.expires_at, t.category_id
+		FROM entries current
+		JOIN feeds f ON f.id = current.feed_id
+		JOIN entries e ON e.user_id = current.user_id AND e.id <> current.id
+		JOIN entry_tags et ON et.entry_id = e.id
+		JOIN tags t ON t.id = et.tag_id
+		WHERE current.id = $2 AND current.user_id = $1
+		  AND %s
+		  AND (t.category_id IS NULL OR t.category_id = f.category_id)
+		  AND NOT EXISTS (SELECT 1 FROM entry_tags applied WHERE applied.entry_id = current.id AND applied.tag_id = t.id)
+		GROUP BY t.id
+		ORDER BY COUNT(*) DESC, t.name ASC
+		LIMIT 10
+	`, entriesCondition)
+	rows, err := s.db.Query(query, append([]any{userID, entryID}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch tag suggestions for entry #%d: %v`, entryID, err)
+	}
+	defer rows.Close()
+
+	tags := make([]model.Tag, 0)
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch tag row: %v`, err)
+		}
+		tags = append(tags, *tag)
+	}
+
+	return tags, nil
+}
+
+// TagOnStar applies the given tag automatically to the entry when it is starred,
+// and removes it when the entry is not starred anymore. Tags applied manually are left untouched.
+func (s *Storage) TagOnStar(userID, entryID int64, tagName string) error {
+	var starred bool
+	err := s.db.QueryRow(`SELECT starred FROM entries WHERE id=$1 AND user_id=$2`, entryID, userID).Scan(&starred)
+	if err != nil {
+		return fmt.Errorf(`store: unable to fetch starred state of entry #%d: %v`, entryID, err)
+	}
+
+	if !starred {
+		query := `
+			DELETE FROM entry_tags et
+			USING tags t
+			WHERE et.tag_id = t.id
+			  AND et.entry_id = $1
+			  AND et.source = $2
+			  AND t.user_id = $3
+			  AND lower(t.name) = lower($4)
+			  AND t.category_id IS NULL
+		`
+		if _, err := s.db.Exec(query, entryID, model.TagSourceAuto, userID, tagName); err != nil {
+			return fmt.Errorf(`store: unable to remove starred tag from entry #%d: %v`, entryID, err)
+		}
+		return nil
+	}
+
+	tag, err := s.GetOrCreateTag(userID, tagName)
+	if err != nil {
+		return err
+	}
+
+	var alreadyTagged bool
+	query := `SELECT true FROM entry_tags WHERE entry_id=$1 AND tag_id=$2`
+	if err := s.db.QueryRow(query, entryID, tag.ID).Scan(&alreadyTagged); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf(`store: unable to check tags of entry #%d: %v`, entryID, err)
+	}
+
+	if alreadyTagged {
+		return nil
+	}
+
+	return s.AddTagToEntry(userID, entryID, tag.ID, model.TagSourceAuto)
+}
+
+// CopyEntryTags applies the tags of an entry to another entry of the same user, keeping their source.
+// Auto tags are only copied when includeAuto is true. A tag already on the target entry is upgraded to manual
+// when it is manual on the source entry. Tags exceeding the limits of the target entry are skipped.
+func (s *Storage) CopyEntryTags(userID, fromEntryID, toEntryID int64, includeAuto bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	var autoTaggingDisabled bool
+	query := `
+		SELECT f.disable_auto_tagging
+		FROM entries e
+		JOIN feeds f ON f.id = e.feed_id
+		WHERE e.id=$1 AND e.user_id=$2
+	`
+	if err := tx.QueryRow(query, toEntryID, userID).Scan(&autoTaggingDisabled); err != nil {
+		tx.Rollback()
+		return fmt.Errorf(`store: entry #%d not found for user #%d: %v`, toEntryID, userID, err)
+	}
+
+	query = `
+		SELECT et.tag_id, et.source
+		FROM entry_tags et
+		JOIN entries e ON e.id = et.entry_id
+		WHERE et.entry_id = $1 AND e.user_id = $2 AND ($3 OR et.source <> $4)
+		ORDER BY et.created_at ASC
+	`
+	rows, err := tx.Query(query, fromEntryID, userID, includeAuto && !autoTaggingDisabled, model.TagSourceAuto)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf(`store: unable to fetch tags of entry #%d: %v`, fromEntryID, err)
+	}
+
+	var entryTags model.EntryTags
+	for rows.Next() {
+		var et model.EntryTag
+		if err := rows.Scan(&et.TagID, &et.Source); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return fmt.Errorf(`store: unable to fetch entry tag row: %v`, err)
+		}
+		entryTags = append(entryTags, &et)
+	}
+	rows.Close()
+
+	query = `
+		INSERT INTO entry_tags (entry_id, tag_id, source)
+		SELECT $1, $2, $3::tag_source
+		WHERE EXISTS (SELECT 1 FROM entry_tags et WHERE et.entry_id = $1 AND et.tag_id = $2)
+		   OR (
+			(SELECT COUNT(*) FROM entry_tags et WHERE et.entry_id = $1) < $5
+			AND ($3 <> $4 OR (SELECT COUNT(*) FROM entry_tags et WHERE et.entry_id = $1 AND et.source = $4) < $6)
+		   )
+		ON CONFLICT (entry_id, tag_id) DO UPDATE SET source = EXCLUDED.source WHERE EXCLUDED.source <> $4
+	`
+	for _, et := range entryTags {
+		_, err := tx.Exec(query,
+			toEntryID,
+			et.TagID,
+			et.Source,
+			model.TagSourceAuto,
+			config.Opts.TagsMaxPerEntry(),
+			config.Opts.TagsMaxAutoPerEntry(),
+		)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf(`store: unable to copy tag #%d to entry #%d: %v`, et.TagID, toEntryID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	for _, et := range entryTags {
+		if err := s.refreshTagExpiry(et.TagID, et.Source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
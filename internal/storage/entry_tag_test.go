@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+)
+
+const skipDatabaseTestsMessage = `Set TEST_MINIFLUX_DATABASE_URL to run the tests against a database`
+
+// openTestDatabase connects to the database of TEST_MINIFLUX_DATABASE_URL and picks a tag having entries.
+func openTestDatabase(tb testing.TB) (db *sql.DB, userID, tagID int64) {
+	dsn := os.Getenv("TEST_MINIFLUX_DATABASE_URL")
+	if dsn == "" {
+		tb.Skip(skipDatabaseTestsMessage)
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	query := `
+		SELECT t.user_id, t.id
+		FROM tags t
+		WHERE EXISTS (SELECT 1 FROM entry_tags et WHERE et.tag_id = t.id)
+		LIMIT 1
+	`
+	if err := db.QueryRow(query).Scan(&userID, &tagID); err != nil {
+		if err == sql.ErrNoRows {
+			tb.Skip(`The test database has no tagged entries`)
+		}
+		tb.Fatal(err)
+	}
+
+	return db, userID, tagID
+}
+
+func TestEntriesWithTagQueryPlans(t *testing.T) {
+	db, userID, tagID := openTestDatabase(t)
+
+	scenarios := []struct {
+		name  string
+		query string
+	}{
+		{"entries with tag", entriesWithTagQuery},
+		{"count entries with tag", countEntriesWithTagQuery},
+	}
+
+	for _, scenario := range scenarios {
+		t.Run(scenario.name, func(t *testing.T) {
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tx.Rollback()
+
+			// Small test databases are cheaper to scan sequentially, only check the index can serve the query.
+			if _, err := tx.Exec(`SET LOCAL enable_seqscan = off`); err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := tx.Query(`EXPLAIN `+scenario.query, tagID, userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+
+			var plan strings.Builder
+			for rows.Next() {
+				var line string
+				if err := rows.Scan(&line); err != nil {
+					t.Fatal(err)
+				}
+				plan.WriteString(line)
+				plan.WriteString("\n")
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(plan.String(), "entry_tags_tag_id_entry_id_idx") {
+				t.Errorf(`The query should use the entry_tags (tag_id, entry_id) index, got:\n%s`, plan.String())
+			}
+		})
+	}
+}
+
+func BenchmarkGetEntriesWithTag(b *testing.B) {
+	db, userID, tagID := openTestDatabase(b)
+	store := NewStorage(db)
+
+	for b.Loop() {
+		if _, err := store.GetEntriesWithTag(userID, tagID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCountEntriesWithTag(b *testing.B) {
+	db, userID, tagID := openTestDatabase(b)
+	store := NewStorage(db)
+
+	for b.Loop() {
+		if _, err := store.CountEntriesWithTag(userID, tagID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestTagSuggestionScore(t *testing.T) {
+	if score := tagSuggestionScore(2, 4, 10, 10, 0); score != 0.5 {
+		t.Errorf(`Without popularity weight, the score should be the neighbor frequency, got %v`, score)
+	}
+
+	if score := tagSuggestionScore(2, 4, 10, 10, 1); score != 1 {
+		t.Errorf(`With a full popularity weight, the most used tag should score 1, got %v`, score)
+	}
+
+	if score := tagSuggestionScore(0, 0, 0, 0, 0.5); score != 0 {
+		t.Errorf(`A tag without neighbors nor entries should score 0, got %v`, score)
+	}
+
+	rare := tagSuggestionScore(3, 10, 1, 100, 0.5)
+	established := tagSuggestionScore(2, 10, 80, 100, 0.5)
+	if rare >= established {
+		t.Errorf(`An established tag should outrank a rare one, got %v and %v`, established, rare)
+	}
+}
@@ -0,0 +1,428 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage // import "miniflux.app/v2/internal/storage"
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"miniflux.app/v2/internal/embedding"
+	"miniflux.app/v2/internal/model"
+)
+
+type scoredEntryID struct {
+	entryID int64
+	score   float64
+}
+
+// FindEntriesBySimilarityToVector returns the entries whose embedding is the most similar to the given vector,
+// most similar first. The vector is typically the embedding of a search query computed by the caller.
+func (s *Storage) FindEntriesBySimilarityToVector(userID int64, queryVector []float32, limit int) (model.Entries, error) {
+	scoredEntries, err := s.FindScoredEntriesBySimilarityToVector(userID, queryVector, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(model.Entries, 0, len(scoredEntries))
+	for _, scoredEntry := range scoredEntries {
+		entries = append(entries, scoredEntry.Entry)
+	}
+
+	return entries, nil
+}
+
+// FindScoredEntriesBySimilarityToVector works like FindEntriesBySimilarityToVector
+// but also returns the similarity score of each entry.
+func (s *Storage) FindScoredEntriesBySimilarityToVector(userID int64, queryVector []float32, limit int) ([]model.ScoredEntry, error) {
+	ranking, err := s.rankEntriesBySimilarity(userID, queryVector, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.entriesInRankingOrder(userID, ranking)
+}
+
+// FindSimilarEntries returns the entries whose embedding is the most similar to the embedding of the given entry,
+// most similar first, along with their similarity score. The entry itself is never part of the results.
+func (s *Storage) FindSimilarEntries(userID, entryID int64, limit int) ([]model.ScoredEntry, error) {
+	var data []byte
+	query := `SELECT embedding FROM entries WHERE id = $1 AND user_id = $2 AND embedding IS NOT NULL`
+	err := s.db.QueryRow(query, entryID, userID).Scan(&data)
+	switch {
+	case err == sql.ErrNoRows:
+		return make([]model.ScoredEntry, 0), nil
+	case err != nil:
+		return nil, fmt.Errorf(`store: unable to fetch embedding of entry #%d: %v`, entryID, err)
+	}
+
+	vector, err := embedding.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to decode embedding of entry #%d: %v`, entryID, err)
+	}
+
+	// Ask for one more result since the entry is its own best match.
+	rankLimit := limit
+	if rankLimit > 0 {
+		rankLimit++
+	}
+
+	ranking, err := s.rankEntriesBySimilarity(userID, vector, rankLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	ranking = slices.DeleteFunc(ranking, func(item scoredEntryID) bool {
+		return item.entryID == entryID
+	})
+
+	if limit > 0 && len(ranking) > limit {
+		ranking = ranking[:limit]
+	}
+
+	return s.entriesInRankingOrder(userID, ranking)
+}
+
+// maxLoggedSkippedEntryIDs caps the number of skipped entry IDs listed in the similarity search warning.
+const maxLoggedSkippedEntryIDs = 20
+
+// rankEntriesBySimilarity computes the cosine similarity between the vector and the embedding of every entry of the user,
+// and returns the best matches. Invalid embeddings and embeddings with a different dimension than the vector,
+// for example computed by a previous model, are ignored and logged.
+func (s *Storage) rankEntriesBySimilarity(userID int64, queryVector []float32, limit int) ([]scoredEntryID, error) {
+	queryVector = embedding.Normalize(queryVector)
+
+	query := `
+		SELECT id, embedding, embedding_normalized
+		FROM entries
+		WHERE user_id = $1 AND status != 'removed' AND embedding IS NOT NULL
+	`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch entry embeddings: %v`, err)
+	}
+	defer rows.Close()
+
+	ranking := make([]scoredEntryID, 0)
+	skipped := 0
+	skippedEntryIDs := make([]int64, 0)
+	for rows.Next() {
+		var entryID int64
+		var data []byte
+		var normalized bool
+		if err := rows.Scan(&entryID, &data, &normalized); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch entry embedding row: %v`, err)
+		}
+
+		vector, err := embedding.Decode(data)
+		if err != nil {
+			slog.Debug("Skipping entry with invalid embedding",
+				slog.Int64("entry_id", entryID),
+				slog.Any("error", err),
+			)
+			skipped++
+			if len(skippedEntryIDs) < maxLoggedSkippedEntryIDs {
+				skippedEntryIDs = append(skippedEntryIDs, entryID)
+			}
+			continue
+		}
+
+		if len(vector) != len(queryVector) {
+			slog.Debug("Skipping entry with mismatched embedding dimension",
+				slog.Int64("entry_id", entryID),
+				slog.Int("dimension", len(vector)),
+				slog.Int("expected_dimension", len(queryVector)),
+			)
+			skipped++
+			if len(skippedEntryIDs) < maxLoggedSkippedEntryIDs {
+				skippedEntryIDs = append(skippedEntryIDs, entryID)
+			}
+			continue
+		}
+
+		if !normalized {
+			vector = embedding.Normalize(vector)
+		}
+
+		ranking = append(ranking, scoredEntryID{entryID: entryID, score: embedding.Dot(queryVector, vector)})
+	}
+
+	if skipped > 0 {
+		slog.Warn("Some embeddings were skipped during similarity search",
+			slog.Int64("user_id", userID),
+			slog.Int("nb_skipped", skipped),
+			slog.Any("skipped_entry_ids", skippedEntryIDs),
+			slog.Int("expected_dimension", len(queryVector)),
+		)
+	}
+
+	sort.SliceStable(ranking, func(i, j int) bool {
+		return ranking[i].score > ranking[j].score
+	})
+
+	if limit > 0 && len(ranking) > limit {
+		ranking = ranking[:limit]
+	}
+
+	return ranking, nil
+}
+
+// entriesInRankingOrder loads the ranked entries along with their score, keeping the order of the ranking.
+func (s *Storage) entriesInRankingOrder(userID int64, ranking []scoredEntryID) ([]model.ScoredEntry, error) {
+	if len(ranking) == 0 {
+		return make([]model.ScoredEntry, 0), nil
+	}
+
+	entryIDs := make([]int64, 0, len(ranking))
+	for _, item := range ranking {
+		entryIDs = append(entryIDs, item.entryID)
+	}
+
+	builder := s.NewEntryQueryBuilder(userID)
+	builder.WithEntryIDs(entryIDs)
+	entries, err := builder.GetEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	entriesByID := make(map[int64]*model.Entry, len(entries))
+	for _, entry := range entries {
+		entriesByID[entry.ID] = entry
+	}
+
+	scoredEntries := make([]model.ScoredEntry, 0, len(entries))
+	for _, item := range ranking {
+		if entry, found := entriesByID[item.entryID]; found {
+			scoredEntries = append(scoredEntries, model.ScoredEntry{Entry: entry, Score: item.score})
+		}
+	}
+
+	return scoredEntries, nil
+}
+
+// RelatedClusters returns the clusters of the user whose centroid is the most similar to the centroid of the given cluster,
+// most related first. The centroid of a cluster is the normalized mean of the embeddings of its entries.
+func (s *Storage) RelatedClusters(userID, clusterID int64, limit int) (model.Clusters, error) {
+	if !s.ClusterIDExists(userID, clusterID) {
+		return nil, ErrClusterNotFound
+	}
+
+	centroids, err := s.clusterCentroids(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	related := make(model.Clusters, 0)
+	reference, found := centroids[clusterID]
+	if !found {
+		return related, nil
+	}
+
+	scores := make(map[int64]float64, len(centroids))
+	for otherClusterID, centroid := range centroids {
+		if otherClusterID != clusterID && len(centroid) == len(reference) {
+			scores[otherClusterID] = embedding.Dot(reference, centroid)
+		}
+	}
+
+	clusters, err := s.Clusters(userID, model.ClusterSortCreatedAt, false, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range clusters {
+		if _, found := scores[cluster.ID]; found {
+			related = append(related, cluster)
+		}
+	}
+
+	sort.SliceStable(related, func(i, j int) bool {
+		return scores[related[i].ID] > scores[related[j].ID]
+	})
+
+	if limit > 0 && len(related) > limit {
+		related = related[:limit]
+	}
+
+	return related, nil
+}
+
+// AssignEntryToBestCluster adds an entry to the active cluster whose centroid is the most similar to its embedding,
+// provided the similarity reaches the threshold. It returns the cluster, or nil when the entry has no embedding
+// or no cluster is similar enough. An entry already in the best cluster is left as is.
+func (s *Storage) AssignEntryToBestCluster(userID, entryID int64, threshold float64) (*model.Cluster, error) {
+	var data []byte
+	var normalized bool
+	query := `SELECT embedding, embedding_normalized FROM entries WHERE id = $1 AND user_id = $2 AND embedding IS NOT NULL`
+	err := s.db.QueryRow(query, entryID, userID).Scan(&data, &normalized)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf(`store: unable to fetch embedding of entry #%d: %v`, entryID, err)
+	}
+
+	vector, err := embedding.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to decode embedding of entry #%d: %v`, entryID, err)
+	}
+
+	if !normalized {
+		vector = embedding.Normalize(vector)
+	}
+
+	centroids, err := s.clusterCentroids(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var bestClusterID int64
+	bestScore := threshold
+	for clusterID, centroid := range centroids {
+		if len(centroid) != len(vector) {
+			continue
+		}
+
+		if score := embedding.Dot(vector, centroid); score >= bestScore {
+			bestClusterID = clusterID
+			bestScore = score
+		}
+	}
+
+	if bestClusterID == 0 {
+		return nil, nil
+	}
+
+	if err := s.AddEntryToCluster(bestClusterID, entryID); err != nil {
+		return nil, err
+	}
+
+	return s.ClusterByID(userID, bestClusterID)
+}
+
+// clusterCentroids returns the stored centroid of every active cluster of the user.
+// Missing centroids, for example of clusters created before centroids were stored, are computed and stored on demand.
+// Clusters without any embedded entry are omitted.
+func (s *Storage) clusterCentroids(userID int64) (map[int64][]float32, error) {
+	query := `
+		SELECT id, centroid
+		FROM clusters
+		WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > NOW())
+	`
+	rows, err := s.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch cluster centroids: %v`, err)
+	}
+
+	centroids := make(map[int64][]float32)
+	var missingCentroids []int64
+	for rows.Next() {
+		var clusterID int64
+		var data []byte
+		if err := rows.Scan(&clusterID, &data); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf(`store: unable to fetch cluster centroid row: %v`, err)
+		}
+
+		if data == nil {
+			missingCentroids = append(missingCentroids, clusterID)
+			continue
+		}
+
+		if centroid, err := embedding.Decode(data); err == nil && len(centroid) > 0 {
+			centroids[clusterID] = centroid
+		}
+	}
+	rows.Close()
+
+	for _, clusterID := range missingCentroids {
+		centroid, err := s.recomputeClusterCentroid(clusterID)
+		if err != nil {
+			return nil, err
+		}
+		if centroid != nil {
+			centroids[clusterID] = centroid
+		}
+	}
+
+	return centroids, nil
+}
+
+// RecomputeClusterCentroid updates the stored centroid of a cluster from the embeddings of its entries.
+// The centroid is cleared when no entry of the cluster has an embedding.
+func (s *Storage) RecomputeClusterCentroid(clusterID int64) error {
+	_, err := s.recomputeClusterCentroid(clusterID)
+	return err
+}
+
+// recomputeClusterCentroid stores and returns the normalized mean of the embeddings of the cluster entries.
+// Embeddings with a different dimension than the first one are ignored and logged.
+func (s *Storage) recomputeClusterCentroid(clusterID int64) ([]float32, error) {
+	query := `
+		SELECT e.id, e.embedding, e.embedding_normalized
+		FROM cluster_entries ce
+		JOIN entries e ON e.id = ce.entry_id
+		WHERE ce.cluster_id = $1 AND e.embedding IS NOT NULL
+	`
+	rows, err := s.db.Query(query, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch embeddings of cluster #%d: %v`, clusterID, err)
+	}
+
+	var sum []float32
+	for rows.Next() {
+		var entryID int64
+		var data []byte
+		var normalized bool
+		if err := rows.Scan(&entryID, &data, &normalized); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf(`store: unable to fetch cluster embedding row: %v`, err)
+		}
+
+		vector, err := embedding.Decode(data)
+		if err != nil || len(vector) == 0 {
+			continue
+		}
+
+		if sum != nil && len(vector) != len(sum) {
+			slog.Warn("Skipping entry with mismatched embedding dimension in cluster centroid",
+				slog.Int64("cluster_id", clusterID),
+				slog.Int64("entry_id", entryID),
+				slog.Int("dimension", len(vector)),
+				slog.Int("expected_dimension", len(sum)),
+			)
+			continue
+		}
+
+		if !normalized {
+			vector = embedding.Normalize(vector)
+		}
+
+		if sum == nil {
+			sum = vector
+			continue
+		}
+
+		for i, value := range vector {
+			sum[i] += value
+		}
+	}
+	rows.Close()
+
+	var centroid []float32
+	var data any
+	if sum != nil {
+		// The direction of the sum is the same as the direction of the mean.
+		centroid = embedding.Normalize(sum)
+		data = embedding.Encode(centroid)
+	}
+
+	if _, err := s.db.Exec(`UPDATE clusters SET centroid = $1 WHERE id = $2`, data, clusterID); err != nil {
+		return nil, fmt.Errorf(`store: unable to update centroid of cluster #%d: %v`, clusterID, err)
+	}
+
+	return centroid, nil
+}
@@ -7,16 +7,48 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/lib/pq"
+
+	"miniflux.app/v2/internal/config"
 	"miniflux.app/v2/internal/model"
 )
 
+// ErrTagNotFound is returned when a tag doesn't exist or belongs to another user.
+var ErrTagNotFound = errors.New("store: tag not found")
+
+// ErrNoTagsToMerge is returned when none of the tags to merge belongs to the user or differs from the target tag.
+var ErrNoTagsToMerge = errors.New("store: no tag to merge")
+
+// bumpTagVersionQuery increments the tag version of a user, it must run after any change to the tags of this user.
+const bumpTagVersionQuery = `UPDATE users SET tag_version = tag_version + 1 WHERE id = $1`
+
+// TagVersion returns a counter incremented each time the tags of a user are created, modified or removed.
+// Clients can compare it with a previous value to detect stale tag data.
+func (s *Storage) TagVersion(userID int64) (int64, error) {
+	var version int64
+	if err := s.db.QueryRow(`SELECT tag_version FROM users WHERE id=$1`, userID).Scan(&version); err != nil {
+		return 0, fmt.Errorf(`store: unable to fetch tag version: %v`, err)
+	}
+
+	return version, nil
+}
+
+func (s *Storage) bumpTagVersion(userID int64) error {
+	if _, err := s.db.Exec(bumpTagVersionQuery, userID); err != nil {
+		return fmt.Errorf(`store: unable to update tag version: %v`, err)
+	}
+
+	return nil
+}
+
 // TagByID returns a tag by its ID.
 func (s *Storage) TagByID(userID, tagID int64) (*model.Tag, error) {
-	var tag model.Tag
-
-	query := `SELECT id, user_id, name, created_at FROM tags WHERE user_id=$1 AND id=$2`
-	err := s.db.QueryRow(query, userID, tagID).Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt)
+	query := `SELECT id, user_id, name, description, pinned, created_at, expires_at, category_id FROM tags WHERE user_id=$1 AND id=$2`
+	tag, err := scanTag(s.db.QueryRow(query, userID, tagID))
 
 	switch {
 	case err == sql.ErrNoRows:
@@ -24,16 +56,80 @@ func (s *Storage) TagByID(userID, tagID int64) (*model.Tag, error) {
 	case err != nil:
 		return nil, fmt.Errorf(`store: unable to fetch tag: %v`, err)
 	default:
-		return &tag, nil
+		return tag, nil
 	}
 }
 
-// TagByName returns a tag by its name for a given user.
-func (s *Storage) TagByName(userID int64, name string) (*model.Tag, error) {
+// scanTag reads the tag columns id, user_id, name, description, pinned, created_at, expires_at and category_id,
+// followed by the extra columns of the row.
+func scanTag(row interface{ Scan(...any) error }, extra ...any) (*model.Tag, error) {
 	var tag model.Tag
+	var description sql.NullString
+	var expiresAt sql.NullTime
+	var categoryID sql.NullInt64
+
+	dest := append([]any{&tag.ID, &tag.UserID, &tag.Name, &description, &tag.Pinned, &tag.CreatedAt, &expiresAt, &categoryID}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if description.Valid {
+		tag.Description = &description.String
+	}
+	if expiresAt.Valid {
+		tag.ExpiresAt = &expiresAt.Time
+	}
+	if categoryID.Valid {
+		tag.CategoryID = &categoryID.Int64
+	}
+
+	return &tag, nil
+}
+
+// TagsByIDs returns the tags of a user having the given IDs, in the order of the IDs.
+// Unknown IDs and IDs of tags belonging to other users are ignored.
+func (s *Storage) TagsByIDs(userID int64, tagIDs []int64) (model.Tags, error) {
+	query := `
+		SELECT id, user_id, name, description, pinned, created_at, expires_at, category_id
+		FROM tags
+		WHERE user_id=$1 AND id = ANY($2)
+		ORDER BY array_position($2, id)
+	`
+	rows, err := s.db.Query(query, userID, pq.Array(tagIDs))
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch tags: %v`, err)
+	}
+	defer rows.Close()
+
+	tags := make(model.Tags, 0, len(tagIDs))
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch tag row: %v`, err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// TagByName returns a global tag by its name for a given user.
+func (s *Storage) TagByName(userID int64, name string) (*model.Tag, error) {
+	return s.TagByNameInCategory(userID, 0, name)
+}
 
-	query := `SELECT id, user_id, name, created_at FROM tags WHERE user_id=$1 AND lower(name)=lower($2)`
-	err := s.db.QueryRow(query, userID, name).Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt)
+// TagByNameInCategory returns a tag by its name for a given user.
+// A tag scoped to the given category takes precedence over a global tag with the same name.
+// Only global tags are considered when categoryID is 0.
+func (s *Storage) TagByNameInCategory(userID, categoryID int64, name string) (*model.Tag, error) {
+	query := `
+		SELECT id, user_id, name, description, pinned, created_at, expires_at, category_id
+		FROM tags
+		WHERE user_id=$1 AND lower(name)=lower($2) AND (category_id IS NULL OR category_id=$3)
+		ORDER BY category_id NULLS LAST
+		LIMIT 1
+	`
+	tag, err := scanTag(s.db.QueryRow(query, userID, name, categoryID))
 
 	switch {
 	case err == sql.ErrNoRows:
@@ -41,14 +137,57 @@ func (s *Storage) TagByName(userID int64, name string) (*model.Tag, error) {
 	case err != nil:
 		return nil, fmt.Errorf(`store: unable to fetch tag by name: %v`, err)
 	default:
-		return &tag, nil
+		return tag, nil
+	}
+}
+
+// TagByNameWithCount finds a tag by name (case-insensitive) along with the number of entries using it.
+// A tag scoped to the given category takes precedence over a global tag, only global tags are considered when categoryID is 0.
+func (s *Storage) TagByNameWithCount(userID, categoryID int64, name string) (*model.Tag, error) {
+	var count int
+
+	query := `
+		SELECT
+			t.id,
+			t.user_id,
+			t.name,
+			t.description,
+			t.pinned,
+			t.created_at,
+			t.expires_at,
+			t.category_id,
+			(SELECT COUNT(*) FROM entry_tags et WHERE et.tag_id = t.id) AS entry_count
+		FROM tags t
+		WHERE t.user_id=$1 AND lower(t.name)=lower($2) AND (t.category_id IS NULL OR t.category_id=$3)
+		ORDER BY t.category_id NULLS LAST
+		LIMIT 1
+	`
+	tag, err := scanTag(s.db.QueryRow(query, userID, name, categoryID), &count)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf(`store: unable to fetch tag by name: %v`, err)
+	default:
+		tag.EntryCount = &count
+		return tag, nil
 	}
 }
 
 // Tags returns all tags for a user.
-func (s *Storage) Tags(userID int64) (model.Tags, error) {
-	query := `SELECT id, user_id, name, created_at FROM tags WHERE user_id=$1 ORDER BY name ASC`
-	rows, err := s.db.Query(query, userID)
+// Only the tags created in the given period are returned when createdSince or createdBefore are set.
+// When categoryID is not 0, only the global tags and the tags scoped to this category are returned.
+func (s *Storage) Tags(userID, categoryID int64, createdSince, createdBefore *time.Time) (model.Tags, error) {
+	conditions, args := tagCreationConditions([]any{userID}, createdSince, createdBefore)
+	conditions, args = tagCategoryConditions(conditions, args, categoryID)
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, t.name, t.description, t.pinned, t.created_at, t.expires_at, t.category_id
+		FROM tags t
+		WHERE t.user_id=$1 %s
+		ORDER BY t.pinned DESC, %s
+	`, conditions, tagNameSorting(config.Opts.TagsSortCollation()))
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to fetch tags: %v`, err)
 	}
@@ -56,32 +195,78 @@ func (s *Storage) Tags(userID int64) (model.Tags, error) {
 
 	tags := make(model.Tags, 0)
 	for rows.Next() {
-		var tag model.Tag
-		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt); err != nil {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch tag row: %v`, err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// TagsNotOnEntry returns the tags of a user that are not applied to the given entry.
+func (s *Storage) TagsNotOnEntry(userID, entryID int64) (model.Tags, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, t.user_id, t.name, t.description, t.pinned, t.created_at, t.expires_at, t.category_id
+		FROM tags t
+		WHERE t.user_id=$1
+		  AND NOT EXISTS (SELECT 1 FROM entry_tags et WHERE et.tag_id = t.id AND et.entry_id = $2)
+		ORDER BY t.pinned DESC, %s
+	`, tagNameSorting(config.Opts.TagsSortCollation()))
+	rows, err := s.db.Query(query, userID, entryID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch tags not on entry: %v`, err)
+	}
+	defer rows.Close()
+
+	tags := make(model.Tags, 0)
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
 			return nil, fmt.Errorf(`store: unable to fetch tag row: %v`, err)
 		}
-		tags = append(tags, &tag)
+		tags = append(tags, tag)
 	}
 
 	return tags, nil
 }
 
 // TagsWithCount returns all tags for a user with entry counts.
-func (s *Storage) TagsWithCount(userID int64) (model.Tags, error) {
-	query := `
+// Only the tags created in the given period are returned when createdSince or createdBefore are set.
+// When categoryID is not 0, only the global tags and the tags scoped to this category are returned.
+func (s *Storage) TagsWithCount(userID, categoryID int64, createdSince, createdBefore *time.Time) (model.Tags, error) {
+	conditions, args := tagCreationConditions([]any{userID}, createdSince, createdBefore)
+	conditions, args = tagCategoryConditions(conditions, args, categoryID)
+	return s.queryTagsWithCount(conditions, "", args)
+}
+
+// UnusedTags returns the tags of a user that are not applied to any entry.
+func (s *Storage) UnusedTags(userID int64) (model.Tags, error) {
+	return s.queryTagsWithCount("", "HAVING COUNT(et.entry_id) = 0", []any{userID})
+}
+
+// queryTagsWithCount returns the tags of the user given as first argument with their entry counts.
+func (s *Storage) queryTagsWithCount(conditions, having string, args []any) (model.Tags, error) {
+	query := fmt.Sprintf(`
 		SELECT
 			t.id,
 			t.user_id,
 			t.name,
+			t.description,
+			t.pinned,
 			t.created_at,
+			t.expires_at,
+			t.category_id,
 			COUNT(et.entry_id) AS entry_count
 		FROM tags t
 		LEFT JOIN entry_tags et ON t.id = et.tag_id
-		WHERE t.user_id = $1
-		GROUP BY t.id, t.user_id, t.name, t.created_at
-		ORDER BY t.name ASC
-	`
-	rows, err := s.db.Query(query, userID)
+		WHERE t.user_id = $1 %s
+		GROUP BY t.id, t.user_id, t.name, t.description, t.pinned, t.created_at, t.expires_at, t.category_id
+		%s
+		ORDER BY t.pinned DESC, %s
+	`, conditions, having, tagNameSorting(config.Opts.TagsSortCollation()))
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to fetch tags with count: %v`, err)
 	}
@@ -89,51 +274,122 @@ func (s *Storage) TagsWithCount(userID int64) (model.Tags, error) {
 
 	tags := make(model.Tags, 0)
 	for rows.Next() {
-		var tag model.Tag
 		var count int
-		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt, &count); err != nil {
+		tag, err := scanTag(rows, &count)
+		if err != nil {
 			return nil, fmt.Errorf(`store: unable to fetch tag row: %v`, err)
 		}
 		tag.EntryCount = &count
-		tags = append(tags, &tag)
+		tags = append(tags, tag)
 	}
 
 	return tags, nil
 }
 
+func tagCreationConditions(args []any, createdSince, createdBefore *time.Time) (string, []any) {
+	var conditions string
+
+	if createdSince != nil {
+		args = append(args, *createdSince)
+		conditions += fmt.Sprintf(" AND t.created_at >= $%d", len(args))
+	}
+
+	if createdBefore != nil {
+		args = append(args, *createdBefore)
+		conditions += fmt.Sprintf(" AND t.created_at < $%d", len(args))
+	}
+
+	return conditions, args
+}
+
+func tagCategoryConditions(conditions string, args []any, categoryID int64) (string, []any) {
+	if categoryID == 0 {
+		return conditions, args
+	}
+
+	args = append(args, categoryID)
+	conditions += fmt.Sprintf(" AND (t.category_id IS NULL OR t.category_id = $%d)", len(args))
+
+	return conditions, args
+}
+
+// tagNameSorting returns the sort expression on tag names, using the given collation when it's not empty.
+func tagNameSorting(collation string) string {
+	if collation == "" {
+		return "t.name ASC"
+	}
+
+	return fmt.Sprintf("t.name COLLATE %s ASC", pq.QuoteIdentifier(collation))
+}
+
 // CreateTag creates a new tag for a user.
 func (s *Storage) CreateTag(userID int64, request *model.TagCreationRequest) (*model.Tag, error) {
-	var tag model.Tag
+	var description sql.NullString
+	var categoryID sql.NullInt64
+
+	if request.Description != nil && *request.Description != "" {
+		description.String = *request.Description
+		description.Valid = true
+	}
+
+	if request.CategoryID != nil {
+		categoryID.Int64 = *request.CategoryID
+		categoryID.Valid = true
+	}
 
 	query := `
-		INSERT INTO tags (user_id, name)
-		VALUES ($1, $2)
-		RETURNING id, user_id, name, created_at
+		INSERT INTO tags (user_id, name, description, category_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, name, description, pinned, created_at, expires_at, category_id
 	`
-	err := s.db.QueryRow(query, userID, request.Name).Scan(
-		&tag.ID,
-		&tag.UserID,
-		&tag.Name,
-		&tag.CreatedAt,
-	)
-
+	tag, err := scanTag(s.db.QueryRow(query, userID, request.Name, description, categoryID))
 	if err != nil {
 		return nil, fmt.Errorf(`store: unable to create tag %q for user ID %d: %v`, request.Name, userID, err)
 	}
 
-	return &tag, nil
+	if err := s.bumpTagVersion(userID); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
 }
 
 // UpdateTag updates an existing tag.
 func (s *Storage) UpdateTag(tag *model.Tag) error {
-	query := `UPDATE tags SET name=$1 WHERE id=$2 AND user_id=$3`
-	_, err := s.db.Exec(query, tag.Name, tag.ID, tag.UserID)
+	var description sql.NullString
+	if tag.Description != nil {
+		description.String = *tag.Description
+		description.Valid = true
+	}
+
+	query := `UPDATE tags SET name=$1, description=$2 WHERE id=$3 AND user_id=$4`
+	_, err := s.db.Exec(query, tag.Name, description, tag.ID, tag.UserID)
 
 	if err != nil {
 		return fmt.Errorf(`store: unable to update tag: %v`, err)
 	}
 
-	return nil
+	return s.bumpTagVersion(tag.UserID)
+}
+
+// PinTag pins or unpins a tag so it is listed before the other tags.
+func (s *Storage) PinTag(userID, tagID int64, pinned bool) error {
+	query := `UPDATE tags SET pinned=$1 WHERE id=$2 AND user_id=$3`
+	result, err := s.db.Exec(query, pinned, tagID, userID)
+	if err != nil {
+		return fmt.Errorf(`store: unable to pin tag: %v`, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf(`store: unable to pin tag: %v`, err)
+	}
+
+	if count == 0 {
+		return errors.New(`store: no tag has been updated`)
+	}
+
+	return s.bumpTagVersion(userID)
 }
 
 // RemoveTag deletes a tag and all its associations with entries.
@@ -153,6 +409,79 @@ func (s *Storage) RemoveTag(userID, tagID int64) error {
 		return errors.New(`store: no tag has been removed`)
 	}
 
+	return s.bumpTagVersion(userID)
+}
+
+// RemoveUnusedTags deletes tags created before the given date that are not applied to any entry.
+func (s *Storage) RemoveUnusedTags(userID int64, olderThan time.Time) (int64, error) {
+	query := `
+		DELETE FROM tags t
+		WHERE t.user_id = $1
+		  AND t.created_at < $2
+		  AND NOT EXISTS (SELECT 1 FROM entry_tags et WHERE et.tag_id = t.id)
+	`
+	result, err := s.db.Exec(query, userID, olderThan)
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to remove unused tags: %v`, err)
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to remove unused tags: %v`, err)
+	}
+
+	if count > 0 {
+		if err := s.bumpTagVersion(userID); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, nil
+}
+
+// RemoveExpiredTags removes all expired tags.
+// Tags applied manually to at least one entry are never removed, even if an expiration date was set.
+func (s *Storage) RemoveExpiredTags() (int64, error) {
+	query := `
+		WITH removed AS (
+			DELETE FROM tags t
+			WHERE t.expires_at IS NOT NULL
+			  AND t.expires_at < NOW()
+			  AND NOT EXISTS (SELECT 1 FROM entry_tags et WHERE et.tag_id = t.id AND et.source = $1)
+			RETURNING t.user_id
+		), bumped AS (
+			UPDATE users SET tag_version = tag_version + 1 WHERE id IN (SELECT user_id FROM removed)
+		)
+		SELECT COUNT(*) FROM removed
+	`
+	var count int64
+	if err := s.db.QueryRow(query, model.TagSourceManual).Scan(&count); err != nil {
+		return 0, fmt.Errorf(`store: unable to remove expired tags: %v`, err)
+	}
+
+	return count, nil
+}
+
+// refreshTagExpiry updates the expiration date of a tag after it was applied with the given source.
+// Manual tags never expire. Tags only applied automatically expire after TAGS_AUTO_EXPIRY_DAYS without new use.
+func (s *Storage) refreshTagExpiry(tagID int64, source string) error {
+	var err error
+	if source == model.TagSourceManual {
+		_, err = s.db.Exec(`UPDATE tags SET expires_at = NULL WHERE id = $1 AND expires_at IS NOT NULL`, tagID)
+	} else if expiryDays := config.Opts.TagsAutoExpiryDays(); expiryDays > 0 {
+		query := `
+			UPDATE tags t
+			SET expires_at = NOW() + INTERVAL '1 day' * $2
+			WHERE t.id = $1
+			  AND NOT EXISTS (SELECT 1 FROM entry_tags et WHERE et.tag_id = t.id AND et.source = $3)
+		`
+		_, err = s.db.Exec(query, tagID, expiryDays, model.TagSourceManual)
+	}
+
+	if err != nil {
+		return fmt.Errorf(`store: unable to update expiration date of tag #%d: %v`, tagID, err)
+	}
+
 	return nil
 }
 
@@ -164,23 +493,122 @@ func (s *Storage) TagIDExists(userID, tagID int64) bool {
 	return result
 }
 
-// TagNameExists checks if a tag with the given name exists for a user.
-func (s *Storage) TagNameExists(userID int64, name string) bool {
+// TagNameExists checks if a tag with the given name exists for a user in the same scope.
+// A nil categoryID refers to the global tags.
+func (s *Storage) TagNameExists(userID int64, categoryID *int64, name string) bool {
 	var result bool
-	query := `SELECT true FROM tags WHERE user_id=$1 AND lower(name)=lower($2) LIMIT 1`
-	s.db.QueryRow(query, userID, name).Scan(&result)
+	query := `SELECT true FROM tags WHERE user_id=$1 AND category_id IS NOT DISTINCT FROM $2 AND lower(name)=lower($3) LIMIT 1`
+	s.db.QueryRow(query, userID, categoryID, name).Scan(&result)
 	return result
 }
 
-// AnotherTagExists checks if another tag exists with the same name.
+// AnotherTagExists checks if another tag exists with the same name in the scope of the given tag.
 func (s *Storage) AnotherTagExists(userID, tagID int64, name string) bool {
 	var result bool
-	query := `SELECT true FROM tags WHERE user_id=$1 AND id != $2 AND lower(name)=lower($3) LIMIT 1`
+	query := `
+		SELECT true
+		FROM tags t
+		JOIN tags current ON current.id = $2
+		WHERE t.user_id=$1 AND t.id != $2 AND lower(t.name)=lower($3)
+		  AND t.category_id IS NOT DISTINCT FROM current.category_id
+		LIMIT 1
+	`
 	s.db.QueryRow(query, userID, tagID, name).Scan(&result)
 	return result
 }
 
+// RelatedTags returns the tags most frequently applied to the same entries as the given tag.
+func (s *Storage) RelatedTags(userID, tagID int64, limit int) ([]model.TagCount, error) {
+	query := `
+		SELECT t.id, t.name, COUNT(*) AS co_occurrences
+		FROM entry_tags source_et
+		JOIN entry_tags et ON et.entry_id = source_et.entry_id AND et.tag_id != source_et.tag_id
+		JOIN tags t ON t.id = et.tag_id
+		WHERE source_et.tag_id = $1 AND t.user_id = $2
+		GROUP BY t.id, t.name
+		ORDER BY co_occurrences DESC, t.name ASC
+		LIMIT $3
+	`
+	rows, err := s.db.Query(query, tagID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch related tags: %v`, err)
+	}
+	defer rows.Close()
+
+	tagCounts := make([]model.TagCount, 0)
+	for rows.Next() {
+		var tagCount model.TagCount
+		if err := rows.Scan(&tagCount.TagID, &tagCount.Name, &tagCount.Count); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch related tag row: %v`, err)
+		}
+		tagCounts = append(tagCounts, tagCount)
+	}
+
+	return tagCounts, nil
+}
+
+// TagCreationHistogram returns the number of tags of a user created per day or per week, oldest first.
+func (s *Storage) TagCreationHistogram(userID int64, bucket string) ([]model.TimelinePoint, error) {
+	if bucket != model.TimelineBucketDay && bucket != model.TimelineBucketWeek {
+		return nil, fmt.Errorf(`store: invalid tag creation histogram bucket %q`, bucket)
+	}
+
+	query := `
+		SELECT date_trunc($2, t.created_at) as bucket, COUNT(*)
+		FROM tags t
+		WHERE t.user_id = $1
+		GROUP BY bucket
+		ORDER BY bucket ASC
+	`
+	rows, err := s.db.Query(query, userID, bucket)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch tag creation histogram: %v`, err)
+	}
+	defer rows.Close()
+
+	histogram := make([]model.TimelinePoint, 0)
+	for rows.Next() {
+		var point model.TimelinePoint
+		if err := rows.Scan(&point.Date, &point.Count); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch tag creation histogram row: %v`, err)
+		}
+		histogram = append(histogram, point)
+	}
+
+	return histogram, nil
+}
+
+// TagFeedBreakdown returns the feeds of the entries having the given tag, with the most tagged entries first.
+func (s *Storage) TagFeedBreakdown(userID, tagID int64) ([]model.FeedCount, error) {
+	query := `
+		SELECT f.id, f.title, COUNT(*) AS entry_count
+		FROM entry_tags et
+		JOIN entries e ON e.id = et.entry_id
+		JOIN feeds f ON f.id = e.feed_id
+		WHERE et.tag_id = $1 AND e.user_id = $2
+		GROUP BY f.id, f.title
+		ORDER BY entry_count DESC, f.title ASC
+	`
+	rows, err := s.db.Query(query, tagID, userID)
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch feed breakdown of tag #%d: %v`, tagID, err)
+	}
+	defer rows.Close()
+
+	feedCounts := make([]model.FeedCount, 0)
+	for rows.Next() {
+		var feedCount model.FeedCount
+		if err := rows.Scan(&feedCount.FeedID, &feedCount.Title, &feedCount.Count); err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch feed breakdown row: %v`, err)
+		}
+		feedCounts = append(feedCounts, feedCount)
+	}
+
+	return feedCounts, nil
+}
+
 // GetOrCreateTag returns an existing tag or creates a new one.
+// When a concurrent call creates the same tag first, the tag created by the other call is returned.
 func (s *Storage) GetOrCreateTag(userID int64, name string) (*model.Tag, error) {
 	tag, err := s.TagByName(userID, name)
 	if err != nil {
@@ -191,7 +619,122 @@ func (s *Storage) GetOrCreateTag(userID int64, name string) (*model.Tag, error)
 		return tag, nil
 	}
 
-	return s.CreateTag(userID, &model.TagCreationRequest{Name: name})
+	tag, err = s.CreateTag(userID, &model.TagCreationRequest{Name: name})
+	if err != nil {
+		// The insert most likely lost a race on the unique constraint, use the tag created in the meantime.
+		if existingTag, fetchErr := s.TagByName(userID, name); fetchErr == nil && existingTag != nil {
+			return existingTag, nil
+		}
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// GetOrCreateTags returns the tags with the given names, creating the missing ones.
+// Names are matched case-insensitively and the result follows the order of the first occurrence of each name.
+func (s *Storage) GetOrCreateTags(userID int64, names []string) (model.Tags, error) {
+	names = uniqueTagNames(names)
+	if len(names) == 0 {
+		return make(model.Tags, 0), nil
+	}
+
+	tagsByName, err := s.tagsByLowerName(userID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	var missingNames []string
+	for _, name := range names {
+		if _, found := tagsByName[strings.ToLower(name)]; !found {
+			missingNames = append(missingNames, name)
+		}
+	}
+
+	if len(missingNames) > 0 {
+		query := `
+			INSERT INTO tags (user_id, name)
+			SELECT $1, unnest($2::text[])
+			ON CONFLICT DO NOTHING
+		`
+		result, err := s.db.Exec(query, userID, pq.Array(missingNames))
+		if err != nil {
+			return nil, fmt.Errorf(`store: unable to create tags: %v`, err)
+		}
+
+		if count, _ := result.RowsAffected(); count > 0 {
+			if err := s.bumpTagVersion(userID); err != nil {
+				return nil, err
+			}
+		}
+
+		createdTags, err := s.tagsByLowerName(userID, missingNames)
+		if err != nil {
+			return nil, err
+		}
+		for name, tag := range createdTags {
+			tagsByName[name] = tag
+		}
+	}
+
+	tags := make(model.Tags, 0, len(names))
+	for _, name := range names {
+		if tag, found := tagsByName[strings.ToLower(name)]; found {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
+}
+
+// tagsByLowerName returns the global tags of the user matching the given names, indexed by lowercase name.
+func (s *Storage) tagsByLowerName(userID int64, names []string) (map[string]*model.Tag, error) {
+	lowerNames := make([]string, 0, len(names))
+	for _, name := range names {
+		lowerNames = append(lowerNames, strings.ToLower(name))
+	}
+
+	query := `
+		SELECT id, user_id, name, description, pinned, created_at, expires_at, category_id
+		FROM tags
+		WHERE user_id=$1 AND lower(name) = ANY($2) AND category_id IS NULL
+		ORDER BY id ASC
+	`
+	rows, err := s.db.Query(query, userID, pq.Array(lowerNames))
+	if err != nil {
+		return nil, fmt.Errorf(`store: unable to fetch tags by name: %v`, err)
+	}
+	defer rows.Close()
+
+	tagsByName := make(map[string]*model.Tag, len(names))
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, fmt.Errorf(`store: unable to fetch tag row: %v`, err)
+		}
+
+		// Keep the oldest tag when several tags only differ by case.
+		if _, found := tagsByName[strings.ToLower(tag.Name)]; !found {
+			tagsByName[strings.ToLower(tag.Name)] = tag
+		}
+	}
+
+	return tagsByName, nil
+}
+
+// uniqueTagNames removes empty names and case-insensitive duplicates, keeping the first occurrence.
+func uniqueTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result
 }
 
 // MergeTags merges multiple tags into one, reassigning all entries.
@@ -201,36 +744,175 @@ func (s *Storage) MergeTags(userID int64, targetTagID int64, sourceTagIDs []int6
 		return fmt.Errorf(`store: unable to begin transaction: %v`, err)
 	}
 
-	// Reassign entries from source tags to target tag
+	if err := mergeTags(tx, userID, targetTagID, sourceTagIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	return nil
+}
+
+// PreviewTagMerge estimates the impact of merging the source tags into the target tag without changing anything.
+// It returns the number of entries that would receive the target tag and the number of entries that already have it.
+func (s *Storage) PreviewTagMerge(userID int64, targetTagID int64, sourceTagIDs []int64) (movedEstimate int, conflictCount int, err error) {
+	query := `
+		SELECT
+			COUNT(DISTINCT et.entry_id) FILTER (WHERE target.entry_id IS NULL),
+			COUNT(DISTINCT et.entry_id) FILTER (WHERE target.entry_id IS NOT NULL)
+		FROM entry_tags et
+		JOIN tags t ON t.id = et.tag_id
+		LEFT JOIN entry_tags target ON target.entry_id = et.entry_id AND target.tag_id = $2
+		WHERE t.user_id = $1 AND et.tag_id = ANY($3) AND et.tag_id <> $2
+	`
+	err = s.db.QueryRow(query, userID, targetTagID, pq.Array(sourceTagIDs)).Scan(&movedEstimate, &conflictCount)
+	if err != nil {
+		return 0, 0, fmt.Errorf(`store: unable to preview tag merge: %v`, err)
+	}
+
+	return movedEstimate, conflictCount, nil
+}
+
+// mergeTags reassigns the entries of the source tags to the target tag and deletes the source tags.
+// It returns ErrTagNotFound when the target tag doesn't belong to the user,
+// and ErrNoTagsToMerge when no source tag is a different tag of the user.
+func mergeTags(tx *sql.Tx, userID int64, targetTagID int64, sourceTagIDs []int64) error {
+	var lockedTagID int64
+	err := tx.QueryRow(`SELECT id FROM tags WHERE id=$1 AND user_id=$2 FOR UPDATE`, targetTagID, userID).Scan(&lockedTagID)
+	switch {
+	case err == sql.ErrNoRows:
+		return ErrTagNotFound
+	case err != nil:
+		return fmt.Errorf(`store: unable to fetch target tag: %v`, err)
+	}
+
+	var sourceTagCount int
+	query := `SELECT COUNT(*) FROM tags WHERE user_id=$1 AND id = ANY($2) AND id <> $3`
+	if err := tx.QueryRow(query, userID, pq.Array(sourceTagIDs), targetTagID).Scan(&sourceTagCount); err != nil {
+		return fmt.Errorf(`store: unable to count source tags: %v`, err)
+	}
+	if sourceTagCount == 0 {
+		return ErrNoTagsToMerge
+	}
+
 	for _, sourceTagID := range sourceTagIDs {
 		if sourceTagID == targetTagID {
 			continue
 		}
 
 		// Insert entries that don't already have the target tag
-		query := `
+		query = `
 			INSERT INTO entry_tags (entry_id, tag_id, source, created_at)
 			SELECT et.entry_id, $1, et.source, et.created_at
 			FROM entry_tags et
-			WHERE et.tag_id = $2
+			JOIN tags t ON t.id = et.tag_id
+			WHERE et.tag_id = $2 AND t.user_id = $3
 			ON CONFLICT (entry_id, tag_id) DO NOTHING
 		`
-		if _, err := tx.Exec(query, targetTagID, sourceTagID); err != nil {
-			tx.Rollback()
+		if _, err := tx.Exec(query, targetTagID, sourceTagID, userID); err != nil {
 			return fmt.Errorf(`store: unable to reassign entries: %v`, err)
 		}
 
 		// Delete the source tag (cascade will remove entry_tags)
 		query = `DELETE FROM tags WHERE id = $1 AND user_id = $2`
 		if _, err := tx.Exec(query, sourceTagID, userID); err != nil {
-			tx.Rollback()
 			return fmt.Errorf(`store: unable to delete source tag: %v`, err)
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	if _, err := tx.Exec(bumpTagVersionQuery, userID); err != nil {
+		return fmt.Errorf(`store: unable to update tag version: %v`, err)
 	}
 
 	return nil
 }
+
+// BulkRenameTags applies a regular expression replacement to every tag name of a user.
+// Tags whose new name collides with an existing tag are merged into it. It returns the number of tags changed.
+func (s *Storage) BulkRenameTags(userID int64, pattern, replacement string) (int, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0, fmt.Errorf(`store: invalid tag rename pattern: %v`, err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf(`store: unable to begin transaction: %v`, err)
+	}
+
+	rows, err := tx.Query(`SELECT id, name, COALESCE(category_id, 0) FROM tags WHERE user_id=$1 ORDER BY id ASC FOR UPDATE`, userID)
+	if err != nil {
+		tx.Rollback()
+		return 0, fmt.Errorf(`store: unable to fetch tags: %v`, err)
+	}
+
+	// Tag names are unique per category, global tags using the category 0.
+	type tagKey struct {
+		categoryID int64
+		name       string
+	}
+
+	var tags []model.Tag
+	var categoryIDs []int64
+	tagIDsByKey := make(map[tagKey]int64)
+	for rows.Next() {
+		var tag model.Tag
+		var categoryID int64
+		if err := rows.Scan(&tag.ID, &tag.Name, &categoryID); err != nil {
+			rows.Close()
+			tx.Rollback()
+			return 0, fmt.Errorf(`store: unable to fetch tag row: %v`, err)
+		}
+		tags = append(tags, tag)
+		categoryIDs = append(categoryIDs, categoryID)
+		tagIDsByKey[tagKey{categoryID, strings.ToLower(tag.Name)}] = tag.ID
+	}
+	rows.Close()
+
+	changed := 0
+	for i, tag := range tags {
+		newName := re.ReplaceAllString(tag.Name, replacement)
+		if newName == tag.Name || newName == "" {
+			continue
+		}
+
+		if len(newName) > 255 {
+			tx.Rollback()
+			return 0, fmt.Errorf(`store: new name for tag #%d is too long`, tag.ID)
+		}
+
+		delete(tagIDsByKey, tagKey{categoryIDs[i], strings.ToLower(tag.Name)})
+
+		newKey := tagKey{categoryIDs[i], strings.ToLower(newName)}
+		if existingTagID, found := tagIDsByKey[newKey]; found {
+			if err := mergeTags(tx, userID, existingTagID, []int64{tag.ID}); err != nil {
+				tx.Rollback()
+				return 0, err
+			}
+		} else {
+			if _, err := tx.Exec(`UPDATE tags SET name=$1 WHERE id=$2 AND user_id=$3`, newName, tag.ID, userID); err != nil {
+				tx.Rollback()
+				return 0, fmt.Errorf(`store: unable to rename tag #%d: %v`, tag.ID, err)
+			}
+			tagIDsByKey[newKey] = tag.ID
+		}
+
+		changed++
+	}
+
+	if changed > 0 {
+		if _, err := tx.Exec(bumpTagVersionQuery, userID); err != nil {
+			tx.Rollback()
+			return 0, fmt.Errorf(`store: unable to update tag version: %v`, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf(`store: unable to commit transaction: %v`, err)
+	}
+
+	return changed, nil
+}
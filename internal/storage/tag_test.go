@@ -0,0 +1,59 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTagCreationConditions(t *testing.T) {
+	conditions, args := tagCreationConditions([]any{int64(1)}, nil, nil)
+	if conditions != "" || len(args) != 1 {
+		t.Errorf(`No condition should be added without dates, got %q with %d args`, conditions, len(args))
+	}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	conditions, args = tagCreationConditions([]any{int64(1)}, &since, &before)
+	expected := " AND t.created_at >= $2 AND t.created_at < $3"
+	if conditions != expected {
+		t.Errorf(`Unexpected conditions, got %q instead of %q`, conditions, expected)
+	}
+
+	if len(args) != 3 || args[1] != since || args[2] != before {
+		t.Errorf(`Unexpected arguments: %v`, args)
+	}
+
+	conditions, args = tagCreationConditions([]any{int64(1)}, nil, &before)
+	if conditions != " AND t.created_at < $2" || len(args) != 2 {
+		t.Errorf(`Unexpected conditions, got %q with %d args`, conditions, len(args))
+	}
+}
+
+func TestTagNameSorting(t *testing.T) {
+	if sorting := tagNameSorting(""); sorting != "t.name ASC" {
+		t.Errorf(`Unexpected sorting without collation: %q`, sorting)
+	}
+
+	if sorting := tagNameSorting("und-x-icu"); sorting != `t.name COLLATE "und-x-icu" ASC` {
+		t.Errorf(`Unexpected sorting with collation: %q`, sorting)
+	}
+}
+
+func TestUniqueTagNames(t *testing.T) {
+	names := uniqueTagNames([]string{"Go", "", "rust", "go", "Rust", "python"})
+	expected := []string{"Go", "rust", "python"}
+
+	if len(names) != len(expected) {
+		t.Fatalf(`Unexpected names: %v`, names)
+	}
+
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf(`Unexpected name at position %d, got %q instead of %q`, i, names[i], expected[i])
+		}
+	}
+}
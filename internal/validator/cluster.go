@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package validator // import "miniflux.app/v2/internal/validator"
+
+import (
+	"time"
+
+	"miniflux.app/v2/internal/locale"
+	"miniflux.app/v2/internal/model"
+)
+
+// ValidateClusterSpec validates the definition of a cluster to create.
+// The name is optional, the cluster is named after its first entry without it.
+func ValidateClusterSpec(spec *model.ClusterSpec) *locale.LocalizedError {
+	if len(spec.Name) > model.ClusterNameMaxLength {
+		return locale.NewLocalizedError("error.cluster_name_too_long")
+	}
+
+	if len(spec.EntryIDs) == 0 {
+		return locale.NewLocalizedError("error.cluster_entry_ids_required")
+	}
+
+	if spec.Source != "" && !IsValidClusterSource(spec.Source) {
+		return locale.NewLocalizedError("error.invalid_cluster_source")
+	}
+
+	return nil
+}
+
+// IsValidClusterSource returns true when the source is manual or auto.
+func IsValidClusterSource(source string) bool {
+	return source == model.ClusterSourceManual || source == model.ClusterSourceAuto
+}
+
+// ValidateClusterBatchCreation validates a request to create several clusters at once.
+func ValidateClusterBatchCreation(request *model.ClusterBatchCreationRequest) *locale.LocalizedError {
+	if len(request.Clusters) == 0 {
+		return locale.NewLocalizedError("error.clusters_required")
+	}
+
+	for i := range request.Clusters {
+		if err := ValidateClusterSpec(&request.Clusters[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateClusterEntriesRequest validates a request to add entries to an existing cluster.
+func ValidateClusterEntriesRequest(request *model.ClusterEntriesRequest) *locale.LocalizedError {
+	if len(request.EntryIDs) == 0 {
+		return locale.NewLocalizedError("error.cluster_entry_ids_required")
+	}
+
+	return nil
+}
+
+// ValidateClusterTagRequest validates a request to tag all the entries of a cluster.
+func ValidateClusterTagRequest(request *model.ClusterTagRequest) *locale.LocalizedError {
+	if request.TagName == "" {
+		return locale.NewLocalizedError("error.tag_name_required")
+	}
+
+	if len(request.TagName) > 255 {
+		return locale.NewLocalizedError("error.tag_name_too_long")
+	}
+
+	if request.Source != "" && request.Source != model.TagSourceManual && request.Source != model.TagSourceAuto {
+		return locale.NewLocalizedError("error.invalid_tag_source")
+	}
+
+	return nil
+}
+
+// ValidateClusterConversionRequest validates a request to turn a cluster into a tag.
+func ValidateClusterConversionRequest(request *model.ClusterConversionRequest) *locale.LocalizedError {
+	if request.TagName == "" {
+		return locale.NewLocalizedError("error.tag_name_required")
+	}
+
+	if len(request.TagName) > 255 {
+		return locale.NewLocalizedError("error.tag_name_too_long")
+	}
+
+	return nil
+}
+
+// ValidateTagConversionRequest validates a request to turn the entries of a tag into a cluster.
+func ValidateTagConversionRequest(request *model.TagConversionRequest) *locale.LocalizedError {
+	if len(request.ClusterName) > 255 {
+		return locale.NewLocalizedError("error.cluster_name_too_long")
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return locale.NewLocalizedError("error.cluster_expiry_in_past")
+	}
+
+	return nil
+}
+
+// ValidateClusterExpiryRequest validates a request to change the expiration date of a cluster.
+func ValidateClusterExpiryRequest(request *model.ClusterExpiryRequest) *locale.LocalizedError {
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return locale.NewLocalizedError("error.cluster_expiry_in_past")
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package validator // import "miniflux.app/v2/internal/validator"
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"miniflux.app/v2/internal/model"
+)
+
+func TestValidateClusterBatchCreation(t *testing.T) {
+	err := ValidateClusterBatchCreation(&model.ClusterBatchCreationRequest{
+		Clusters: []model.ClusterSpec{
+			{Name: "Topic A", EntryIDs: []int64{1, 2}},
+			{Name: "Topic B", EntryIDs: []int64{3}},
+		},
+	})
+	if err != nil {
+		t.Error(`A valid request should not be rejected`)
+	}
+
+	err = ValidateClusterBatchCreation(&model.ClusterBatchCreationRequest{})
+	if err == nil {
+		t.Error(`An empty list of clusters is not valid`)
+	}
+
+	err = ValidateClusterBatchCreation(&model.ClusterBatchCreationRequest{
+		Clusters: []model.ClusterSpec{{EntryIDs: []int64{1}}},
+	})
+	if err != nil {
+		t.Error(`A cluster without name is named after its first entry and should not be rejected`)
+	}
+
+	err = ValidateClusterBatchCreation(&model.ClusterBatchCreationRequest{
+		Clusters: []model.ClusterSpec{{Name: strings.Repeat("a", 256), EntryIDs: []int64{1}}},
+	})
+	if err == nil {
+		t.Error(`A cluster name longer than 255 characters is not valid`)
+	}
+
+	err = ValidateClusterBatchCreation(&model.ClusterBatchCreationRequest{
+		Clusters: []model.ClusterSpec{{Name: "Topic A"}},
+	})
+	if err == nil {
+		t.Error(`A cluster without entries is not valid`)
+	}
+
+	err = ValidateClusterBatchCreation(&model.ClusterBatchCreationRequest{
+		Clusters: []model.ClusterSpec{{Name: "Topic A", Source: model.ClusterSourceAuto, EntryIDs: []int64{1}}},
+	})
+	if err != nil {
+		t.Error(`An auto cluster should not be rejected`)
+	}
+
+	err = ValidateClusterBatchCreation(&model.ClusterBatchCreationRequest{
+		Clusters: []model.ClusterSpec{{Name: "Topic A", Source: "imported", EntryIDs: []int64{1}}},
+	})
+	if err == nil {
+		t.Error(`A cluster with an unknown source is not valid`)
+	}
+}
+
+func TestValidateClusterEntriesRequest(t *testing.T) {
+	if err := ValidateClusterEntriesRequest(&model.ClusterEntriesRequest{EntryIDs: []int64{1}}); err != nil {
+		t.Error(`A valid request should not be rejected`)
+	}
+
+	if err := ValidateClusterEntriesRequest(&model.ClusterEntriesRequest{}); err == nil {
+		t.Error(`An empty list of entries is not valid`)
+	}
+}
+
+func TestValidateClusterExpiryRequest(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	if err := ValidateClusterExpiryRequest(&model.ClusterExpiryRequest{ExpiresAt: &future}); err != nil {
+		t.Error(`An expiration date in the future should be accepted`)
+	}
+
+	if err := ValidateClusterExpiryRequest(&model.ClusterExpiryRequest{}); err != nil {
+		t.Error(`Clearing the expiration date should be accepted`)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if err := ValidateClusterExpiryRequest(&model.ClusterExpiryRequest{ExpiresAt: &past}); err == nil {
+		t.Error(`An expiration date in the past is not valid`)
+	}
+}
+
+func TestValidateClusterTagRequest(t *testing.T) {
+	if err := ValidateClusterTagRequest(&model.ClusterTagRequest{TagName: "elections", Source: model.TagSourceAuto}); err != nil {
+		t.Error(`A valid request should not be rejected`)
+	}
+
+	if err := ValidateClusterTagRequest(&model.ClusterTagRequest{}); err == nil {
+		t.Error(`An empty tag name is not valid`)
+	}
+
+	if err := ValidateClusterTagRequest(&model.ClusterTagRequest{TagName: "elections", Source: "invalid"}); err == nil {
+		t.Error(`An invalid source is not valid`)
+	}
+}
+
+func TestValidateTagConversionRequest(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	if err := ValidateTagConversionRequest(&model.TagConversionRequest{ClusterName: "Elections", ExpiresAt: &future}); err != nil {
+		t.Error(`A valid request should not be rejected`)
+	}
+
+	if err := ValidateTagConversionRequest(&model.TagConversionRequest{}); err != nil {
+		t.Error(`A request without name nor expiry should not be rejected`)
+	}
+
+	if err := ValidateTagConversionRequest(&model.TagConversionRequest{ClusterName: strings.Repeat("a", 256)}); err == nil {
+		t.Error(`A cluster name longer than 255 characters is not valid`)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if err := ValidateTagConversionRequest(&model.TagConversionRequest{ExpiresAt: &past}); err == nil {
+		t.Error(`An expiration date in the past is not valid`)
+	}
+}
+
+func TestValidateClusterConversionRequest(t *testing.T) {
+	if err := ValidateClusterConversionRequest(&model.ClusterConversionRequest{TagName: "elections", DeleteCluster: true}); err != nil {
+		t.Error(`A valid request should not be rejected`)
+	}
+
+	if err := ValidateClusterConversionRequest(&model.ClusterConversionRequest{}); err == nil {
+		t.Error(`An empty tag name is not valid`)
+	}
+
+	if err := ValidateClusterConversionRequest(&model.ClusterConversionRequest{TagName: strings.Repeat("a", 256)}); err == nil {
+		t.Error(`A tag name longer than 255 characters is not valid`)
+	}
+}
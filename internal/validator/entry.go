@@ -29,6 +29,34 @@ func ValidateEntryStatus(status string) error {
 	return fmt.Errorf(`invalid entry status, valid status values are: "%s", "%s" and "%s"`, model.EntryStatusRead, model.EntryStatusUnread, model.EntryStatusRemoved)
 }
 
+// ValidateSummaryFormat makes sure the entry summary format is valid.
+func ValidateSummaryFormat(format string) error {
+	switch format {
+	case model.SummaryFormatParagraph, model.SummaryFormatBullets:
+		return nil
+	}
+
+	return fmt.Errorf(`invalid summary format, valid format values are: "%s" and "%s"`, model.SummaryFormatParagraph, model.SummaryFormatBullets)
+}
+
+// ValidateEntryEmbeddingRequest makes sure an imported embedding is usable.
+// The dimension must match the existing embeddings of the same model when there is any, 0 means no constraint.
+func ValidateEntryEmbeddingRequest(request *model.EntryEmbeddingRequest, expectedDimension int) error {
+	if len(request.Vector) == 0 {
+		return errors.New(`the embedding vector cannot be empty`)
+	}
+
+	if expectedDimension > 0 && len(request.Vector) != expectedDimension {
+		return fmt.Errorf(`invalid embedding dimension %d, the existing embeddings of this model have %d dimensions`, len(request.Vector), expectedDimension)
+	}
+
+	if len(request.Model) > 255 {
+		return errors.New(`the embedding model name is too long`)
+	}
+
+	return nil
+}
+
 // ValidateEntryOrder makes sure the sorting order is valid.
 func ValidateEntryOrder(order string) error {
 	switch order {
@@ -57,3 +57,36 @@ func TestValidateEntryOrder(t *testing.T) {
 		t.Error(`An invalid order should generate a error`)
 	}
 }
+
+func TestValidateSummaryFormat(t *testing.T) {
+	for _, format := range []string{model.SummaryFormatParagraph, model.SummaryFormatBullets} {
+		if err := ValidateSummaryFormat(format); err != nil {
+			t.Errorf(`A valid format should not generate any error: %q`, format)
+		}
+	}
+
+	for _, format := range []string{"", "markdown", "Bullets"} {
+		if err := ValidateSummaryFormat(format); err == nil {
+			t.Errorf(`An invalid format should generate an error: %q`, format)
+		}
+	}
+}
+
+func TestValidateEntryEmbeddingRequest(t *testing.T) {
+	request := &model.EntryEmbeddingRequest{Vector: []float32{0.1, 0.2, 0.3}, Model: "custom-model"}
+	if err := ValidateEntryEmbeddingRequest(request, 3); err != nil {
+		t.Errorf(`A valid embedding should not generate any error: %v`, err)
+	}
+
+	if err := ValidateEntryEmbeddingRequest(request, 0); err != nil {
+		t.Errorf(`Any dimension should be accepted without existing embeddings: %v`, err)
+	}
+
+	if err := ValidateEntryEmbeddingRequest(request, 4); err == nil {
+		t.Error(`A dimension mismatch should generate an error`)
+	}
+
+	if err := ValidateEntryEmbeddingRequest(&model.EntryEmbeddingRequest{}, 0); err == nil {
+		t.Error(`An empty vector should generate an error`)
+	}
+}
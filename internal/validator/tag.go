@@ -4,6 +4,8 @@
 package validator // import "miniflux.app/v2/internal/validator"
 
 import (
+	"fmt"
+
 	"miniflux.app/v2/internal/locale"
 	"miniflux.app/v2/internal/model"
 	"miniflux.app/v2/internal/storage"
@@ -19,7 +21,15 @@ func ValidateTagCreation(store *storage.Storage, userID int64, request *model.Ta
 		return locale.NewLocalizedError("error.tag_name_too_long")
 	}
 
-	if store.TagNameExists(userID, request.Name) {
+	if request.Description != nil && len(*request.Description) > 2000 {
+		return locale.NewLocalizedError("error.tag_description_too_long")
+	}
+
+	if request.CategoryID != nil && !store.CategoryIDExists(userID, *request.CategoryID) {
+		return locale.NewLocalizedError("error.category_not_found")
+	}
+
+	if store.TagNameExists(userID, request.CategoryID, request.Name) {
 		return locale.NewLocalizedError("error.tag_already_exists")
 	}
 
@@ -42,9 +52,37 @@ func ValidateTagModification(store *storage.Storage, userID, tagID int64, reques
 		}
 	}
 
+	if request.Description != nil && len(*request.Description) > 2000 {
+		return locale.NewLocalizedError("error.tag_description_too_long")
+	}
+
+	return nil
+}
+
+// ValidateTagBulkRename validates a request to rename tags with a regular expression.
+func ValidateTagBulkRename(request *model.TagBulkRenameRequest) *locale.LocalizedError {
+	if request.Pattern == "" || !IsValidRegex(request.Pattern) {
+		return locale.NewLocalizedError("error.tag_rename_invalid_pattern")
+	}
+
 	return nil
 }
 
+// ValidateTagMergeRequest validates a request to merge tags into a target tag.
+func ValidateTagMergeRequest(targetTagID int64, request *model.TagMergeRequest) *locale.LocalizedError {
+	if len(request.SourceTagIDs) == 0 {
+		return locale.NewLocalizedError("error.tag_ids_required")
+	}
+
+	for _, sourceTagID := range request.SourceTagIDs {
+		if sourceTagID != targetTagID {
+			return nil
+		}
+	}
+
+	return locale.NewLocalizedError("error.tag_merge_into_itself")
+}
+
 // ValidateEntryTagRequest validates a request to add tags to an entry.
 func ValidateEntryTagRequest(request *model.EntryTagRequest) *locale.LocalizedError {
 	if len(request.TagIDs) == 0 {
@@ -75,3 +113,13 @@ func ValidateEntryTagByNameRequest(request *model.EntryTagByNameRequest) *locale
 
 	return nil
 }
+
+// ValidateTagSource makes sure the entry tag source is valid.
+func ValidateTagSource(source string) error {
+	switch source {
+	case model.TagSourceManual, model.TagSourceAuto:
+		return nil
+	}
+
+	return fmt.Errorf(`invalid tag source, valid source values are: "%s" and "%s"`, model.TagSourceManual, model.TagSourceAuto)
+}
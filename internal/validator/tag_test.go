@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: Copyright The Miniflux Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package validator // import "miniflux.app/v2/internal/validator"
+
+import (
+	"testing"
+
+	"miniflux.app/v2/internal/model"
+)
+
+func TestValidateTagSource(t *testing.T) {
+	for _, source := range []string{model.TagSourceManual, model.TagSourceAuto} {
+		if err := ValidateTagSource(source); err != nil {
+			t.Errorf(`A valid source should not generate any error: %q`, source)
+		}
+	}
+
+	for _, source := range []string{"", "invalid", "Manual"} {
+		if err := ValidateTagSource(source); err == nil {
+			t.Errorf(`An invalid source should generate an error: %q`, source)
+		}
+	}
+}
+
+func TestValidateTagBulkRename(t *testing.T) {
+	if err := ValidateTagBulkRename(&model.TagBulkRenameRequest{Pattern: `^topic/`, Replacement: ""}); err != nil {
+		t.Error(`A valid pattern should not generate any error`)
+	}
+
+	if err := ValidateTagBulkRename(&model.TagBulkRenameRequest{Pattern: ""}); err == nil {
+		t.Error(`An empty pattern should generate an error`)
+	}
+
+	if err := ValidateTagBulkRename(&model.TagBulkRenameRequest{Pattern: `[`}); err == nil {
+		t.Error(`An invalid pattern should generate an error`)
+	}
+}
+
+func TestValidateTagMergeRequest(t *testing.T) {
+	if err := ValidateTagMergeRequest(3, &model.TagMergeRequest{SourceTagIDs: []int64{1, 2}}); err != nil {
+		t.Error(`A request with source tags should not generate any error`)
+	}
+
+	if err := ValidateTagMergeRequest(3, &model.TagMergeRequest{}); err == nil {
+		t.Error(`A request without source tags should generate an error`)
+	}
+
+	if err := ValidateTagMergeRequest(1, &model.TagMergeRequest{SourceTagIDs: []int64{1, 2}}); err != nil {
+		t.Error(`A request including the target tag along with other tags should not generate any error`)
+	}
+
+	if err := ValidateTagMergeRequest(1, &model.TagMergeRequest{SourceTagIDs: []int64{1, 1}}); err == nil {
+		t.Error(`A request merging only the target tag into itself should generate an error`)
+	}
+}
@@ -147,6 +147,10 @@ func ValidateUserModification(store *storage.Storage, userID int64, changes *mod
 		}
 	}
 
+	if changes.StarredTagName != nil && len(*changes.StarredTagName) > 255 {
+		return locale.NewLocalizedError("error.tag_name_too_long")
+	}
+
 	return nil
 }
 
@@ -5,9 +5,12 @@ package validator // import "miniflux.app/v2/internal/validator"
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"regexp"
 	"strings"
+
+	"miniflux.app/v2/internal/model"
 )
 
 var domainRegex = regexp.MustCompile(`^(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,}$`)
@@ -25,6 +28,16 @@ func ValidateRange(offset, limit int) error {
 	return nil
 }
 
+// ValidateTimelineBucket makes sure the timeline bucket size is valid.
+func ValidateTimelineBucket(bucket string) error {
+	switch bucket {
+	case model.TimelineBucketDay, model.TimelineBucketWeek:
+		return nil
+	}
+
+	return fmt.Errorf(`invalid bucket, valid bucket values are: "%s" and "%s"`, model.TimelineBucketDay, model.TimelineBucketWeek)
+}
+
 // ValidateDirection makes sure the sorting direction is valid.
 func ValidateDirection(direction string) error {
 	switch direction {
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"miniflux.app/v2/internal/locale"
+	"miniflux.app/v2/internal/model"
 )
 
 func TestIsValidURL(t *testing.T) {
@@ -38,6 +39,18 @@ func TestValidateRange(t *testing.T) {
 	}
 }
 
+func TestValidateTimelineBucket(t *testing.T) {
+	for _, bucket := range []string{model.TimelineBucketDay, model.TimelineBucketWeek} {
+		if err := ValidateTimelineBucket(bucket); err != nil {
+			t.Errorf(`A valid bucket should not generate any error: %q`, bucket)
+		}
+	}
+
+	if err := ValidateTimelineBucket("month"); err == nil {
+		t.Error(`An invalid bucket should generate a error`)
+	}
+}
+
 func TestValidateDirection(t *testing.T) {
 	for _, status := range []string{"asc", "desc"} {
 		if err := ValidateDirection(status); err != nil {